@@ -1,12 +1,20 @@
 package main
 
 import (
+	"flag"
 	"github.com/diproducts/application-tracker-go/internal/app"
 	"github.com/diproducts/application-tracker-go/internal/config"
 )
 
 func main() {
+	demo := flag.Bool("demo", false, "run with an in-memory storage seeded with fake data")
+	flag.Parse()
+
 	cfg := config.MustLoad()
 
+	if *demo {
+		cfg.DB.Driver = config.DriverMemory
+	}
+
 	app.Run(cfg)
 }
@@ -1,22 +1,180 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"github.com/diproducts/application-tracker-go/internal/config"
 	"github.com/diproducts/application-tracker-go/internal/migrator"
+	"github.com/diproducts/application-tracker-go/migrations"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
 )
 
+// Exit codes. exitPending lets CI tell "schema is behind" apart from failures.
+const (
+	exitOK      = 0
+	exitError   = 1
+	exitUsage   = 2
+	exitPending = 3
+)
+
+const migrationsRoot = "./migrations"
+
+const usage = `Usage: migrator [flags] <command> [args]
+
+Migrations embedded into the binary for the configured db.driver are used
+unless -migrations-path is set.
+
+Commands:
+  up                   apply all pending migrations
+  up-to <version>      apply pending migrations up to and including version
+  down                 roll back the most recent migration
+  down-to <version>    roll back migrations down to, but not including, version
+  redo                 roll back the most recent migration and apply it again
+  reset                roll back all migrations
+  status               print migration status, exits with 3 if some are pending
+  version              print the current database version
+  create <name>        create a new sql migration, for every dialect
+                       unless -migrations-path is set
+
+Flags:
+`
+
 func main() {
-	cfg := config.MustLoad()
+	os.Exit(run())
+}
 
-	var migrationsPath string
+func run() int {
+	var (
+		migrationsPath string
+		dryRun         bool
+	)
 
-	flag.StringVar(&migrationsPath, "migrations-path", "", "path to migrations")
+	flag.StringVar(&migrationsPath, "migrations-path", "", "path to migrations (default: embedded migrations)")
+	flag.BoolVar(&dryRun, "dry-run", false, "print the SQL that would run instead of applying it")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	if migrationsPath == "" {
-		panic("migrations-path flag is required")
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+
+		return exitUsage
+	}
+
+	command, args := args[0], args[1:]
+
+	if command == "create" {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "create requires a migration name")
+
+			return exitUsage
+		}
+
+		dirs := []string{migrationsPath}
+		if migrationsPath == "" {
+			dirs = dirs[:0]
+			for _, dialect := range migrations.Dialects {
+				dirs = append(dirs, filepath.Join(migrationsRoot, dialect))
+			}
+		}
+
+		for _, dir := range dirs {
+			if err := migrator.Create(dir, args[0]); err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR]", err)
+
+				return exitError
+			}
+		}
+
+		return exitOK
+	}
+
+	switch command {
+	case "up", "up-to", "down", "down-to", "redo", "reset", "status", "version":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", command)
+		flag.Usage()
+
+		return exitUsage
+	}
+
+	cfg := config.MustLoad()
+
+	var fsys fs.FS
+	if migrationsPath != "" {
+		fsys = os.DirFS(migrationsPath)
+	} else {
+		embedded, err := migrations.ForDialect(cfg.DB.Driver)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR]", err)
+
+			return exitError
+		}
+
+		fsys = embedded
+	}
+
+	m, err := migrator.New(&cfg.DB, fsys, os.Stdout, dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] failed to init migrator:", err)
+
+		return exitError
+	}
+	defer m.Close()
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		err = m.Up(ctx)
+	case "up-to", "down-to":
+		if len(args) != 1 {
+			fmt.Fprintf(os.Stderr, "%s requires a version\n", command)
+
+			return exitUsage
+		}
+
+		version, parseErr := strconv.ParseInt(args[0], 10, 64)
+		if parseErr != nil {
+			fmt.Fprintf(os.Stderr, "invalid version %q\n", args[0])
+
+			return exitUsage
+		}
+
+		if command == "up-to" {
+			err = m.UpTo(ctx, version)
+		} else {
+			err = m.DownTo(ctx, version)
+		}
+	case "down":
+		err = m.Down(ctx)
+	case "redo":
+		err = m.Redo(ctx)
+	case "reset":
+		err = m.Reset(ctx)
+	case "status":
+		err = m.Status(ctx)
+	case "version":
+		err = m.Version(ctx)
+	}
+
+	if err != nil {
+		if errors.Is(err, migrator.ErrPendingMigrations) {
+			return exitPending
+		}
+
+		fmt.Fprintln(os.Stderr, "[ERROR]", err)
+
+		return exitError
 	}
 
-	migrator.Up(&cfg.DB, migrationsPath)
+	return exitOK
 }
@@ -3,17 +3,25 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/diproducts/application-tracker-go/internal/config"
 	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
 	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/redact"
 	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
-	"github.com/diproducts/application-tracker-go/internal/repository/storage/postgresql"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/realip"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/requestlog"
 	"github.com/diproducts/application-tracker-go/internal/transport/http/routers"
 	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/diproducts/application-tracker-go/internal/worker"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 )
 
 const (
@@ -23,30 +31,207 @@ const (
 )
 
 func Run(cfg *config.Config) {
-	log := setupLogger(cfg.Env)
+	log, err := setupLogger(cfg)
+	if err != nil {
+		slog.Error("failed to set up logger", sl.Err(err))
+		return
+	}
+
+	store, err := initStorage(context.Background(), log, cfg)
+	if err != nil {
+		log.Error("failed to init storage", sl.Err(err))
+		return
+	}
+	defer func() {
+		if err := store.close(); err != nil {
+			log.Error("failed to close db connection", sl.Err(err))
+			return
+		}
+
+		log.Info("db connection closed")
+	}()
+
+	// New hashes use Argon2id, bcrypt hashes are upgraded on login.
+	argon2Params := password_hasher.DefaultArgon2idParams
+	argon2Params.Memory = cfg.Password.Argon2Memory
+	argon2Params.Iterations = cfg.Password.Argon2Iterations
+	argon2Params.Parallelism = cfg.Password.Argon2Parallelism
+	argon2idHasher, err := password_hasher.NewArgon2idPasswordHasher(argon2Params)
+	if err != nil {
+		log.Error("failed to init password hasher", sl.Err(err))
+		return
+	}
+	passwordHasher := password_hasher.NewMultiPasswordHasher(
+		argon2idHasher,
+		password_hasher.NewBcryptPasswordHasher(),
+	)
+
+	tokenManager, keySet, err := initTokenManager(log, cfg)
+	if err != nil {
+		log.Error("failed to init token manager", sl.Err(err))
+		return
+	}
 
-	db, err := postgresql.InitDB(&cfg.DB)
+	verificationTokenManager, err := initVerificationTokenManager(log, cfg)
 	if err != nil {
-		log.Error("failed to init db connection", sl.Err(err))
+		log.Error("failed to init verification token manager", sl.Err(err))
 		return
 	}
-	defer db.Close()
 
-	passwordHasher := password_hasher.NewBcryptPasswordHasher()
-	userRepository := postgresql.NewUserRepository(db)
+	mfaTokenManager, err := initMFATokenManager(&cfg.TwoFactor)
+	if err != nil {
+		log.Error("failed to init mfa token manager", sl.Err(err))
+		return
+	}
 
-	tokenManager := tokenutil.NewJWTTokenManager(
-		cfg.AccessSecret,
-		cfg.RefreshSecret,
-		cfg.AccessTokenTTL,
-		cfg.RefreshTokenTTL,
+	mailSender, err := initMailer(log, cfg.Env, &cfg.Mail)
+	if err != nil {
+		log.Error("failed to init mailer", sl.Err(err))
+		return
+	}
+
+	trustedProxies, err := realip.ParseTrustedProxies(cfg.HTTPServer.TrustedProxies)
+	if err != nil {
+		log.Error("failed to parse trusted proxies", sl.Err(err))
+		return
+	}
+
+	userUsecase := usecase.NewUserUsecase(
+		passwordHasher,
+		store.user,
+		store.token,
+		store.twoFactor,
+		tokenManager,
+		verificationTokenManager,
+		mfaTokenManager,
+		mailSender,
+		usecase.VerificationOptions{
+			URL:      cfg.Verification.URL,
+			Required: cfg.Verification.Required,
+		},
+		usecase.TwoFactorOptions{
+			Enabled: cfg.TwoFactor.Enabled,
+			Issuer:  cfg.TwoFactor.Issuer,
+		},
+		log,
+	)
+	loginGuardUsecase := usecase.NewLoginGuardUsecase(
+		userUsecase,
+		store.loginAttempt,
+		usecase.LockoutOptions{
+			MaxAttempts:   cfg.Lockout.MaxAttempts,
+			MaxIPAttempts: cfg.Lockout.MaxIPAttempts,
+			Window:        cfg.Lockout.Window,
+			Duration:      cfg.Lockout.Duration,
+			MaxDuration:   cfg.Lockout.MaxDuration,
+		},
+		log,
+	)
+	passwordResetUsecase := usecase.NewPasswordResetUsecase(
+		passwordHasher,
+		store.user,
+		store.passwordReset,
+		store.token,
+		loginGuardUsecase,
+		mailSender,
+		usecase.PasswordResetOptions{
+			URL:      cfg.PasswordReset.URL,
+			TokenTTL: cfg.PasswordReset.TokenTTL,
+		},
+		log,
 	)
+	oidcUsecase := usecase.NewOIDCUsecase(
+		passwordHasher,
+		store.user,
+		store.identity,
+		tokenutil.NewOIDCStateManager(cfg.OIDC.Secret, cfg.OIDC.StateTTL),
+		userUsecase,
+		log,
+	)
+	if err := initOIDCProviders(log, &cfg.OIDC, oidcUsecase); err != nil {
+		log.Error("failed to init identity providers", sl.Err(err))
+		return
+	}
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(store.apiKey, log)
+	sessionUsecase := usecase.NewSessionUsecase(store.token, cfg.RefreshTokenTTL, log)
+	applicationUsecase := usecase.NewApplicationUsecase(store.application, log)
+	phaseUsecase := usecase.NewApplicationPhaseUsecase(store.phase, log)
+	rules, err := rateLimitRules(&cfg.RateLimit)
+	if err != nil {
+		log.Error("invalid rate limits", sl.Err(err))
+		return
+	}
+	rateLimitStore, err := initRateLimitStore(&cfg.RateLimit, store)
+	if err != nil {
+		log.Error("failed to init rate limit store", sl.Err(err))
+		return
+	}
+	rateLimitUsecase := usecase.NewRateLimitUsecase(rateLimitStore, rules, log)
+	healthUsecase := usecase.NewHealthUsecase(store.pinger, store.checker, log)
+
+	if cfg.DB.Driver == config.DriverMemory {
+		if err := seedDemoData(context.Background(), log, store.user, userUsecase, applicationUsecase, phaseUsecase); err != nil {
+			log.Error("failed to seed demo data", sl.Err(err))
+			return
+		}
+	}
+
+	// Every purger runs on the same interval. They are all created
+	// before any starts, so an invalid interval stops the start up.
+	purgers := make([]*worker.Periodic, 0, 5)
+	for _, w := range []struct {
+		name string
+		job  worker.Job
+	}{
+		{"token_purger", userUsecase.PurgeExpiredTokens},
+		{"session_purger", sessionUsecase.PurgeExpiredSessions},
+		{"reset_token_purger", passwordResetUsecase.PurgeExpiredTokens},
+		{"login_attempt_purger", loginGuardUsecase.PurgeExpiredAttempts},
+		{"rate_limit_purger", rateLimitUsecase.PurgeIdleBuckets},
+	} {
+		purger, err := worker.NewPeriodic(log, w.name, cfg.TokenPurgeInterval, w.job)
+		if err != nil {
+			log.Error("failed to init worker", sl.Err(err))
+			return
+		}
+
+		purgers = append(purgers, purger)
+	}
+
+	// ctx is the root context of the application. It outlives the HTTP server
+	// so in-flight requests can finish while the server is draining.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	userUsecase := usecase.NewUserUsecase(passwordHasher, userRepository, tokenManager, log)
+	var workers sync.WaitGroup
+
+	for _, purger := range purgers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			purger.Run(ctx)
+		}()
+	}
 
-	ctx := context.TODO()
 	router := chi.NewRouter()
-	router.Mount("/api", routers.NewAPIRouter(ctx, log, userUsecase))
+	router.Use(middleware.RequestID)
+	router.Use(realip.New(trustedProxies))
+	router.Use(requestlog.New(log))
+	router.Mount("/", routers.NewHealthRoutes(log, healthUsecase))
+	if keySet != nil {
+		router.Mount("/.well-known", routers.NewWellKnownRoutes(keySet))
+	}
+	router.Mount("/api", routers.NewAPIRouter(ctx, log, routers.APIDependencies{
+		Users:         userUsecase,
+		Logins:        loginGuardUsecase,
+		PasswordReset: passwordResetUsecase,
+		OIDC:          oidcUsecase,
+		APIKeys:       apiKeyUsecase,
+		Sessions:      sessionUsecase,
+		Applications:  applicationUsecase,
+		Phases:        phaseUsecase,
+		RateLimiter:   rateLimitUsecase,
+	}))
 
 	srv := &http.Server{
 		Addr:         cfg.HTTPServer.Address,
@@ -56,34 +241,78 @@ func Run(cfg *config.Config) {
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	log.Info("server starting", slog.String("address", srv.Addr))
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info("server starting", slog.String("address", srv.Addr))
 
-	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case sig := <-stop:
+		log.Info("shutdown signal received", slog.String("signal", sig.String()))
+	case err := <-serverErr:
 		log.Error("error starting server", sl.Err(err))
 	}
 
-	log.Error("server stopped")
+	// Shutdown order: stop accepting requests and drain in-flight ones,
+	// then stop background workers, then close the db pool (deferred above).
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancelShutdown()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to gracefully shutdown server", sl.Err(err))
+	} else {
+		log.Info("server stopped")
+	}
+
+	cancel()
+	workers.Wait()
 
-	// TODO: graceful shutdown
+	log.Info("background workers stopped")
 }
 
-func setupLogger(env string) *slog.Logger {
-	var log *slog.Logger
+// setupLogger builds the logger from the log config. The level and the format
+// default to the env: debug text locally, debug JSON in dev and info JSON elsewhere.
+// Sensitive attributes are redacted whatever the handler.
+func setupLogger(cfg *config.Config) (*slog.Logger, error) {
+	const op = "app.setupLogger"
 
-	switch env {
+	level, format := slog.LevelInfo, config.LogFormatJSON
+	switch cfg.Env {
 	case envLocal:
-		log = slog.New(
-			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
+		level, format = slog.LevelDebug, config.LogFormatText
 	case envDev:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
-	case envProd:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
+		level = slog.LevelDebug
+	}
+
+	if cfg.Log.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if cfg.Log.Format != "" {
+		format = cfg.Log.Format
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch format {
+	case config.LogFormatText:
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	case config.LogFormatJSON:
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	default:
+		return nil, fmt.Errorf("%s: unknown log format %q", op, format)
 	}
 
-	return log
+	return slog.New(redact.NewHandler(handler)), nil
 }
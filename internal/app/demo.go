@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"log/slog"
+	"time"
+)
+
+const (
+	demoEmail        = "demo@example.com"
+	demoPassword     = "demo-password"
+	demoApplications = 25
+)
+
+// demoTimeline is the order in which a successful application goes through its phases.
+var demoTimeline = []string{
+	models.PhaseApplied,
+	models.PhaseScreening,
+	models.PhaseInterview,
+	models.PhaseOnsite,
+	models.PhaseOffer,
+	models.PhaseAccepted,
+}
+
+// seedDemoData creates a verified demo user with fake applications and their timelines.
+func seedDemoData(
+	ctx context.Context,
+	log *slog.Logger,
+	userRepository userRepository,
+	userUsecase *usecase.UserUsecase,
+	applicationUsecase *usecase.ApplicationUsecase,
+	phaseUsecase *usecase.ApplicationPhaseUsecase,
+) error {
+	const op = "app.seedDemoData"
+
+	userID, err := userUsecase.CreateUser(ctx, demoEmail, demoPassword, gofakeit.Name(), "")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The demo account has no mailbox to confirm the email from.
+	if err := userRepository.VerifyUser(ctx, userID, demoEmail, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for range demoApplications {
+		appID, err := applicationUsecase.CreateApplication(ctx, userID, models.Application{
+			CompanyName:    gofakeit.Company(),
+			Position:       gofakeit.JobTitle(),
+			Url:            gofakeit.URL(),
+			JobDescription: gofakeit.Paragraph(2, 4, 12, "\n\n"),
+			Contacts:       fmt.Sprintf("%s <%s>, %s", gofakeit.Name(), gofakeit.Email(), gofakeit.Phone()),
+			CoverLetter:    gofakeit.Paragraph(1, 5, 15, "\n"),
+			OfferedSalary:  gofakeit.Number(40, 200) * 1000,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, phase := range demoPhases(appID) {
+			if _, err := phaseUsecase.AddPhase(ctx, userID, phase); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	// The credentials are meant to be seen, so they are logged under a key the redaction of secrets leaves alone.
+	log.Info(
+		"demo data seeded",
+		slog.String("demo_login", demoEmail+" / "+demoPassword),
+		slog.Int("applications", demoApplications),
+	)
+
+	return nil
+}
+
+// demoPhases returns a random prefix of demoTimeline spread over the last months.
+// Unfinished timelines are sometimes closed with a rejection or a withdrawal.
+func demoPhases(applicationID int64) []models.ApplicationPhase {
+	n := gofakeit.Number(1, len(demoTimeline))
+	date := gofakeit.DateRange(time.Now().AddDate(0, -6, 0), time.Now().AddDate(0, -1, 0)).UTC()
+
+	names := append([]string{}, demoTimeline[:n]...)
+	if n < len(demoTimeline) && gofakeit.Bool() {
+		names = append(names, gofakeit.RandomString([]string{models.PhaseRejected, models.PhaseWithdrawn}))
+	}
+
+	phases := make([]models.ApplicationPhase, 0, len(names))
+	for _, name := range names {
+		phases = append(phases, models.ApplicationPhase{
+			Name:          name,
+			Date:          date,
+			Notes:         gofakeit.Sentence(10),
+			ApplicationID: applicationID,
+		})
+
+		date = date.AddDate(0, 0, gofakeit.Number(2, 14))
+	}
+
+	return phases
+}
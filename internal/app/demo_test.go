@@ -0,0 +1,73 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/redact"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeedDemoData_LogsUsableCredentials(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+
+	var buf bytes.Buffer
+	log := slog.New(redact.NewHandler(slog.NewJSONHandler(&buf, nil)))
+	quiet := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	mailSender, err := initMailer(quiet, envLocal, &config.Mail{Transport: config.MailTransportLog})
+	require.NoError(t, err)
+
+	users := memory.NewUserRepository(db)
+	userUsecase := usecase.NewUserUsecase(
+		password_hasher.NewBcryptPasswordHasher(),
+		users,
+		memory.NewTokenRepository(db),
+		memory.NewTwoFactorRepository(db),
+		tokenutil.NewJWTTokenManager("access", "refresh", time.Minute, time.Hour),
+		tokenutil.NewVerificationTokenManager("verification", time.Hour),
+		nil,
+		mailSender,
+		usecase.VerificationOptions{Required: true},
+		usecase.TwoFactorOptions{},
+		quiet,
+	)
+
+	err = seedDemoData(
+		ctx,
+		log,
+		users,
+		userUsecase,
+		usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), quiet),
+		usecase.NewApplicationPhaseUsecase(memory.NewApplicationPhaseRepository(db), quiet),
+	)
+	require.NoError(t, err)
+
+	var line struct {
+		Msg       string `json:"msg"`
+		DemoLogin string `json:"demo_login"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "demo data seeded", line.Msg)
+	assert.NotContains(t, buf.String(), redact.Placeholder)
+
+	email, password, ok := strings.Cut(line.DemoLogin, " / ")
+	require.True(t, ok, line.DemoLogin)
+
+	tokens, err := userUsecase.Login(ctx, email, password, models.Client{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.Access)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
+	"log/slog"
+)
+
+// initMailer creates the mail transport selected by cfg.Transport
+// and the templates the emails are rendered from. The log transport
+// doesn't deliver anything, so it is refused in prod.
+func initMailer(log *slog.Logger, env string, cfg *config.Mail) (*mailer.TemplateMailer, error) {
+	const op = "app.initMailer"
+
+	var transport mailer.Mailer
+
+	switch cfg.Transport {
+	case config.MailTransportLog:
+		if env == envProd {
+			return nil, fmt.Errorf("%s: mail transport %q can't be used in %s", op, cfg.Transport, env)
+		}
+
+		transport = mailer.NewLogMailer(log)
+	case config.MailTransportFile:
+		transport = mailer.NewFileMailer(cfg.Dir, cfg.From)
+	case config.MailTransportSMTP:
+		transport = mailer.NewSMTPMailer(mailer.SMTPOptions{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.From,
+			Timeout:  cfg.SMTP.Timeout,
+		})
+	default:
+		return nil, fmt.Errorf("%s: unsupported mail transport %q", op, cfg.Transport)
+	}
+
+	templates, err := mailer.NewTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("mailer initialized", slog.String("transport", cfg.Transport))
+
+	return mailer.NewTemplateMailer(transport, templates), nil
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/oidc"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// defaultOIDCScopes are requested from providers configured without scopes.
+var defaultOIDCScopes = []string{"email", "profile"}
+
+// initOIDCProviders registers the identity providers of cfg. Their metadata is
+// discovered on first use, so a provider that is down doesn't prevent the start.
+func initOIDCProviders(log *slog.Logger, cfg *config.OIDC, oidcUsecase *usecase.OIDCUsecase) error {
+	const op = "app.initOIDCProviders"
+
+	if len(cfg.Providers) == 0 {
+		return nil
+	}
+
+	if cfg.Secret == "" {
+		return fmt.Errorf("%s: oidc.secret is required to use identity providers", op)
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	for name, p := range cfg.Providers {
+		if p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return fmt.Errorf("%s: provider %q: issuer, client_id and redirect_url are required", op, name)
+		}
+
+		scopes := p.Scopes
+		if len(scopes) == 0 {
+			scopes = slices.Clone(defaultOIDCScopes)
+		}
+
+		oidcUsecase.AddProvider(name, oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       scopes,
+		}, client))
+
+		log.Info("identity provider registered", slog.String("provider", name), slog.String("issuer", p.Issuer))
+	}
+
+	return nil
+}
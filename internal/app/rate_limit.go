@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+)
+
+// rateLimitRules turns the limits of cfg into rules. The global limit applies to every
+// route without its own. No rules are returned when rate limiting is disabled.
+func rateLimitRules(cfg *config.RateLimit) ([]usecase.RateLimitRule, error) {
+	const op = "app.rateLimitRules"
+
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	rules := []usecase.RateLimitRule{{
+		Route: "/",
+		Limit: models.RateLimit{Requests: cfg.Requests, Period: cfg.Period, Burst: cfg.Burst},
+	}}
+	for _, r := range cfg.Routes {
+		rules = append(rules, usecase.RateLimitRule{
+			Route: r.Route,
+			Limit: models.RateLimit{Requests: r.Requests, Period: r.Period, Burst: r.Burst},
+		})
+	}
+
+	for _, r := range rules {
+		if r.Limit.Requests <= 0 || r.Limit.Period <= 0 || r.Limit.Burst < 0 {
+			return nil, fmt.Errorf("%s: route %q: requests and period must be positive", op, r.Route)
+		}
+	}
+
+	return rules, nil
+}
+
+// initRateLimitStore returns the bucket store selected by cfg.Store: the database
+// of store or the memory of this process.
+func initRateLimitStore(cfg *config.RateLimit, store *storage) (rateLimitRepository, error) {
+	const op = "app.initRateLimitStore"
+
+	switch cfg.Store {
+	case config.RateLimitStoreDB:
+		return store.rateLimit, nil
+	case config.RateLimitStoreMemory:
+		return memory.NewRateLimitRepository(memory.NewDB()), nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported rate limit store %q", op, cfg.Store)
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"log/slog"
+	"os"
+)
+
+// initTokenManager signs access tokens with the configured signing keys, or with
+// the access secret when there are none. The key set is nil in the latter case.
+func initTokenManager(log *slog.Logger, cfg *config.Config) (*tokenutil.JWTTokenManager, *tokenutil.KeySet, error) {
+	const op = "app.initTokenManager"
+
+	if len(cfg.Signing.Keys) == 0 {
+		if cfg.AccessSecret == "" {
+			return nil, nil, fmt.Errorf("%s: access_secret or signing keys are required", op)
+		}
+
+		tm := tokenutil.NewJWTTokenManager(
+			cfg.AccessSecret,
+			cfg.RefreshSecret,
+			cfg.AccessTokenTTL,
+			cfg.RefreshTokenTTL,
+		)
+
+		return tm, nil, nil
+	}
+
+	keys := make([]tokenutil.SigningKey, 0, len(cfg.Signing.Keys))
+	for _, k := range cfg.Signing.Keys {
+		data, err := os.ReadFile(k.File)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: key %q: %w", op, k.ID, err)
+		}
+
+		privateKey, err := tokenutil.ParsePrivateKey(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: key %q: %w", op, k.ID, err)
+		}
+
+		keys = append(keys, tokenutil.SigningKey{
+			ID:         k.ID,
+			PrivateKey: privateKey,
+			NotBefore:  k.NotBefore,
+			RetireAt:   k.RetireAt,
+		})
+	}
+
+	keySet, err := tokenutil.NewKeySet(keys...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, k := range keySet.JWKS().Keys {
+		log.Info("signing key loaded", slog.String("kid", k.KeyID), slog.String("alg", k.Algorithm))
+	}
+
+	tm := tokenutil.NewKeySetJWTTokenManager(
+		keySet,
+		cfg.RefreshSecret,
+		cfg.AccessTokenTTL,
+		cfg.RefreshTokenTTL,
+	)
+
+	return tm, keySet, nil
+}
+
+// initVerificationTokenManager signs verification tokens with verification.secret.
+// Without it a key derived from refresh_secret is used, unless verification is required.
+func initVerificationTokenManager(log *slog.Logger, cfg *config.Config) (*tokenutil.VerificationTokenManager, error) {
+	const op = "app.initVerificationTokenManager"
+
+	secret := cfg.Verification.Secret
+	if secret == "" {
+		if cfg.Verification.Required {
+			return nil, fmt.Errorf("%s: verification.secret is required when verification.required is set", op)
+		}
+
+		log.Warn("verification.secret is not set, using a key derived from refresh_secret")
+
+		secret = tokenutil.DeriveSecret(cfg.RefreshSecret, "verification")
+	}
+
+	return tokenutil.NewVerificationTokenManager(secret, cfg.Verification.TokenTTL), nil
+}
+
+// initMFATokenManager returns the mfa token manager if two-factor authentication is enabled.
+func initMFATokenManager(cfg *config.TwoFactor) (*tokenutil.MFATokenManager, error) {
+	const op = "app.initMFATokenManager"
+
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("%s: two_factor.secret is required to enable two-factor authentication", op)
+	}
+
+	return tokenutil.NewMFATokenManager(cfg.Secret, cfg.TokenTTL), nil
+}
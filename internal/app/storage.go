@@ -0,0 +1,244 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/migrator"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/postgresql"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/sqlite"
+	"github.com/diproducts/application-tracker-go/migrations"
+	"github.com/jmoiron/sqlx"
+	"log/slog"
+	"time"
+)
+
+type userRepository interface {
+	SaveUser(ctx context.Context, user *models.User) (int64, error)
+	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	VerifyUser(ctx context.Context, id int64, email string, at time.Time) error
+	UpdatePassword(ctx context.Context, id int64, hashedPassword string) error
+	UpdateUser(ctx context.Context, user *models.User) error
+	DeleteUser(ctx context.Context, id int64) error
+}
+
+type tokenRepository interface {
+	BlacklistToken(ctx context.Context, userID int64, tokenID string, expiry time.Time) error
+	IsBlacklisted(ctx context.Context, tokenID string) (bool, error)
+	SaveFamily(ctx context.Context, session models.Session) error
+	UseFamily(ctx context.Context, familyID string, client models.Client, at time.Time) error
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUserFamilies(ctx context.Context, userID int64) error
+	DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error)
+	Sessions(ctx context.Context, userID int64, since time.Time) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID int64, id string) error
+	DeleteExpiredFamilies(ctx context.Context, before time.Time) (int64, error)
+}
+
+type passwordResetRepository interface {
+	SaveResetToken(ctx context.Context, userID int64, tokenHash string, expiry time.Time) error
+	ConsumeResetToken(ctx context.Context, tokenHash string, now time.Time) (int64, error)
+	DeleteUserResetTokens(ctx context.Context, userID int64) error
+	DeleteExpiredResetTokens(ctx context.Context, before time.Time) (int64, error)
+}
+
+type twoFactorRepository interface {
+	SaveTOTP(ctx context.Context, userID int64, secret string) error
+	TOTP(ctx context.Context, userID int64) (models.TOTP, error)
+	ConfirmTOTP(ctx context.Context, userID int64, at time.Time, codeHashes []string) error
+	UseTOTPStep(ctx context.Context, userID, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error
+	DeleteTOTP(ctx context.Context, userID int64) error
+}
+
+type apiKeyRepository interface {
+	SaveAPIKey(ctx context.Context, key *models.APIKey, keyHash string) (int64, error)
+	APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	UseAPIKey(ctx context.Context, keyHash string, at time.Time) (models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id, userID int64) error
+}
+
+type identityRepository interface {
+	SaveIdentity(ctx context.Context, identity *models.UserIdentity) error
+	Identity(ctx context.Context, provider, subject string) (models.UserIdentity, error)
+}
+
+type loginAttemptRepository interface {
+	LoginAttempts(ctx context.Context, key string) (models.LoginAttempts, error)
+	ReserveLoginAttempt(
+		ctx context.Context,
+		key string,
+		at time.Time,
+		since time.Time,
+		maxAttempts int,
+		lockUntil time.Time,
+	) (models.LoginAttempts, bool, error)
+	ReleaseLoginAttempt(ctx context.Context, key string, maxAttempts int) error
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginAttempts(ctx context.Context, key string) error
+	DeleteExpiredLoginAttempts(ctx context.Context, before time.Time) (int64, error)
+}
+
+type rateLimitRepository interface {
+	TakeToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (models.RateLimitResult, error)
+	DeleteIdleBuckets(ctx context.Context, before time.Time) (int64, error)
+}
+
+type applicationRepository interface {
+	SaveApplication(ctx context.Context, app *models.Application) (int64, error)
+	Application(ctx context.Context, id, ownerID int64) (models.Application, error)
+	Applications(ctx context.Context, ownerID int64, q models.ApplicationQuery) (models.ApplicationPage, error)
+	SearchApplications(ctx context.Context, ownerID int64, q string, limit int) ([]models.ApplicationSearchHit, error)
+	UpdateApplication(ctx context.Context, app *models.Application) error
+	DeleteApplication(ctx context.Context, id, ownerID int64) error
+}
+
+type phaseRepository interface {
+	SavePhase(ctx context.Context, ownerID int64, phase *models.ApplicationPhase) (int64, error)
+	Phases(ctx context.Context, ownerID, applicationID int64) ([]models.ApplicationPhase, error)
+	UpdatePhase(ctx context.Context, ownerID int64, phase *models.ApplicationPhase) error
+	DeletePhase(ctx context.Context, ownerID, applicationID, id int64) error
+	ReorderPhases(ctx context.Context, ownerID, applicationID int64, ids []int64) error
+}
+
+// repositories are the repositories of the storage backend selected by db.driver.
+type repositories struct {
+	user          userRepository
+	token         tokenRepository
+	passwordReset passwordResetRepository
+	twoFactor     twoFactorRepository
+	apiKey        apiKeyRepository
+	identity      identityRepository
+	loginAttempt  loginAttemptRepository
+	rateLimit     rateLimitRepository
+	application   applicationRepository
+	phase         phaseRepository
+}
+
+type dbPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type migrationChecker interface {
+	HasPending(ctx context.Context) (bool, error)
+}
+
+// storage is the backend selected by db.driver together with
+// what the readiness probe needs to check it.
+type storage struct {
+	repositories
+	pinger  dbPinger
+	checker migrationChecker
+	close   func() error
+}
+
+// initStorage opens the database selected by cfg.DB.Driver, applies pending
+// migrations if cfg.AutoMigrate is set and creates the repositories.
+func initStorage(ctx context.Context, log *slog.Logger, cfg *config.Config) (*storage, error) {
+	const op = "app.initStorage"
+
+	var (
+		db    *sqlx.DB
+		repos repositories
+		err   error
+	)
+
+	switch cfg.DB.Driver {
+	case config.DriverPostgres:
+		db, err = postgresql.InitDB(&cfg.DB)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		repos = repositories{
+			user:          postgresql.NewUserRepository(db),
+			token:         postgresql.NewTokenRepository(db),
+			passwordReset: postgresql.NewPasswordResetRepository(db),
+			twoFactor:     postgresql.NewTwoFactorRepository(db),
+			apiKey:        postgresql.NewAPIKeyRepository(db),
+			identity:      postgresql.NewIdentityRepository(db),
+			loginAttempt:  postgresql.NewLoginAttemptRepository(db),
+			rateLimit:     postgresql.NewRateLimitRepository(db),
+			application:   postgresql.NewApplicationRepository(db),
+			phase:         postgresql.NewApplicationPhaseRepository(db),
+		}
+	case config.DriverSQLite:
+		db, err = sqlite.InitDB(&cfg.DB)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		repos = repositories{
+			user:          sqlite.NewUserRepository(db),
+			token:         sqlite.NewTokenRepository(db),
+			passwordReset: sqlite.NewPasswordResetRepository(db),
+			twoFactor:     sqlite.NewTwoFactorRepository(db),
+			apiKey:        sqlite.NewAPIKeyRepository(db),
+			identity:      sqlite.NewIdentityRepository(db),
+			loginAttempt:  sqlite.NewLoginAttemptRepository(db),
+			rateLimit:     sqlite.NewRateLimitRepository(db),
+			application:   sqlite.NewApplicationRepository(db),
+			phase:         sqlite.NewApplicationPhaseRepository(db),
+		}
+	case config.DriverMemory:
+		memDB := memory.NewDB()
+
+		return &storage{
+			repositories: repositories{
+				user:          memory.NewUserRepository(memDB),
+				token:         memory.NewTokenRepository(memDB),
+				passwordReset: memory.NewPasswordResetRepository(memDB),
+				twoFactor:     memory.NewTwoFactorRepository(memDB),
+				apiKey:        memory.NewAPIKeyRepository(memDB),
+				identity:      memory.NewIdentityRepository(memDB),
+				loginAttempt:  memory.NewLoginAttemptRepository(memDB),
+				rateLimit:     memory.NewRateLimitRepository(memDB),
+				application:   memory.NewApplicationRepository(memDB),
+				phase:         memory.NewApplicationPhaseRepository(memDB),
+			},
+			pinger:  memDB,
+			checker: memDB,
+			close:   func() error { return nil },
+		}, nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported database driver %q", op, cfg.DB.Driver)
+	}
+
+	checker, err := initMigrations(ctx, log, db, cfg)
+	if err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &storage{
+		repositories: repos,
+		pinger:       db,
+		checker:      checker,
+		close:        db.Close,
+	}, nil
+}
+
+// initMigrations applies pending migrations if cfg.AutoMigrate is set
+// and returns a checker for the readiness probe.
+func initMigrations(ctx context.Context, log *slog.Logger, db *sqlx.DB, cfg *config.Config) (migrationChecker, error) {
+	migrationsFS, err := migrations.ForDialect(cfg.DB.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.AutoMigrate {
+		applied, err := migrator.Apply(ctx, db.DB, migrationsFS, cfg.DB.Driver)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("migrations applied", slog.Int("count", applied))
+	}
+
+	return migrator.NewChecker(db.DB, migrationsFS, cfg.DB.Driver)
+}
@@ -3,21 +3,156 @@ package config
 import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
+	"net/url"
 	"os"
 	"time"
 )
 
 type Config struct {
-	Env             string        `yaml:"env" env-required:"true"`
-	AccessSecret    string        `yaml:"access_secret" env:"ACCESS_SECRET" env-required:"true"`
-	RefreshSecret   string        `yaml:"refresh_secret" env:"REFRESH_SECRET" env-required:"true"`
-	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL" env-required:"true"`
-	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL" env-required:"true"`
-	DB              Database      `yaml:"db"`
-	HTTPServer      HTTPServer    `yaml:"http_server"`
+	Env                string        `yaml:"env" env-required:"true"`
+	AccessSecret       string        `yaml:"access_secret" env:"ACCESS_SECRET"`
+	RefreshSecret      string        `yaml:"refresh_secret" env:"REFRESH_SECRET" env-required:"true"`
+	AccessTokenTTL     time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL" env-required:"true"`
+	RefreshTokenTTL    time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL" env-required:"true"`
+	TokenPurgeInterval time.Duration `yaml:"token_purge_interval" env:"TOKEN_PURGE_INTERVAL" env-default:"1h"`
+	AutoMigrate        bool          `yaml:"auto_migrate" env:"AUTO_MIGRATE" env-default:"false"`
+	Log                Log           `yaml:"log"`
+	Verification       Verification  `yaml:"verification"`
+	PasswordReset      PasswordReset `yaml:"password_reset"`
+	Password           Password      `yaml:"password"`
+	Lockout            Lockout       `yaml:"lockout"`
+	RateLimit          RateLimit     `yaml:"rate_limit"`
+	TwoFactor          TwoFactor     `yaml:"two_factor"`
+	Signing            Signing       `yaml:"signing"`
+	OIDC               OIDC          `yaml:"oidc"`
+	Mail               Mail          `yaml:"mail"`
+	DB                 Database      `yaml:"db"`
+	HTTPServer         HTTPServer    `yaml:"http_server"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Log leaves the level and the format to the env when they are empty.
+type Log struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+type Verification struct {
+	Secret   string        `yaml:"secret" env:"VERIFICATION_SECRET"`
+	TokenTTL time.Duration `yaml:"token_ttl" env:"VERIFICATION_TOKEN_TTL" env-default:"24h"`
+	URL      string        `yaml:"url" env:"VERIFICATION_URL"`
+	Required bool          `yaml:"required" env:"VERIFICATION_REQUIRED" env-default:"false"`
+}
+
+type PasswordReset struct {
+	TokenTTL time.Duration `yaml:"token_ttl" env:"PASSWORD_RESET_TOKEN_TTL" env-default:"1h"`
+	URL      string        `yaml:"url" env:"PASSWORD_RESET_URL"`
+}
+
+type Password struct {
+	Argon2Memory      uint32 `yaml:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY" env-default:"19456"`
+	Argon2Iterations  uint32 `yaml:"argon2_iterations" env:"PASSWORD_ARGON2_ITERATIONS" env-default:"2"`
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM" env-default:"1"`
+}
+
+type Lockout struct {
+	MaxAttempts   int           `yaml:"max_attempts" env:"LOCKOUT_MAX_ATTEMPTS" env-default:"5"`
+	MaxIPAttempts int           `yaml:"max_ip_attempts" env:"LOCKOUT_MAX_IP_ATTEMPTS" env-default:"20"`
+	Window        time.Duration `yaml:"window" env:"LOCKOUT_WINDOW" env-default:"15m"`
+	Duration      time.Duration `yaml:"duration" env:"LOCKOUT_DURATION" env-default:"1m"`
+	MaxDuration   time.Duration `yaml:"max_duration" env:"LOCKOUT_MAX_DURATION" env-default:"1h"`
+}
+
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreDB     = "db"
+)
+
+// RateLimit keeps the buckets in the database by default, so that replicas share them.
+// The memory store is faster but every replica limits on its own.
+type RateLimit struct {
+	Enabled  bool             `yaml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"true"`
+	Store    string           `yaml:"store" env:"RATE_LIMIT_STORE" env-default:"db"`
+	Requests int              `yaml:"requests" env:"RATE_LIMIT_REQUESTS" env-default:"300"`
+	Period   time.Duration    `yaml:"period" env:"RATE_LIMIT_PERIOD" env-default:"1m"`
+	Burst    int              `yaml:"burst" env:"RATE_LIMIT_BURST"`
+	Routes   []RateLimitRoute `yaml:"routes"`
+}
+
+type RateLimitRoute struct {
+	Route    string        `yaml:"route"`
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+}
+
+type TwoFactor struct {
+	Enabled  bool          `yaml:"enabled" env:"TWO_FACTOR_ENABLED" env-default:"false"`
+	Secret   string        `yaml:"secret" env:"TWO_FACTOR_SECRET"`
+	TokenTTL time.Duration `yaml:"token_ttl" env:"TWO_FACTOR_TOKEN_TTL" env-default:"5m"`
+	Issuer   string        `yaml:"issuer" env:"TWO_FACTOR_ISSUER" env-default:"Application Tracker"`
+}
+
+type Signing struct {
+	Keys []SigningKey `yaml:"keys"`
+}
+
+type SigningKey struct {
+	ID        string    `yaml:"id"`
+	File      string    `yaml:"file"`
+	NotBefore time.Time `yaml:"not_before"`
+	RetireAt  time.Time `yaml:"retire_at"`
+}
+
+type OIDC struct {
+	Secret    string                  `yaml:"secret" env:"OIDC_SECRET"`
+	StateTTL  time.Duration           `yaml:"state_ttl" env:"OIDC_STATE_TTL" env-default:"10m"`
+	Timeout   time.Duration           `yaml:"timeout" env:"OIDC_TIMEOUT" env-default:"10s"`
+	Providers map[string]OIDCProvider `yaml:"providers"`
+}
+
+type OIDCProvider struct {
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+const (
+	MailTransportLog  = "log"
+	MailTransportFile = "file"
+	MailTransportSMTP = "smtp"
+)
+
+type Mail struct {
+	Transport string `yaml:"transport" env:"MAIL_TRANSPORT" env-default:"log"`
+	From      string `yaml:"from" env:"MAIL_FROM" env-default:"Application Tracker <no-reply@localhost>"`
+	Dir       string `yaml:"dir" env:"MAIL_DIR" env-default:"mail"`
+	SMTP      SMTP   `yaml:"smtp"`
+}
+
+type SMTP struct {
+	Host     string        `yaml:"host" env:"SMTP_HOST" env-default:"localhost"`
+	Port     int           `yaml:"port" env:"SMTP_PORT" env-default:"587"`
+	Username string        `yaml:"username" env:"SMTP_USERNAME"`
+	Password string        `yaml:"password" env:"SMTP_PASSWORD"`
+	Timeout  time.Duration `yaml:"timeout" env:"SMTP_TIMEOUT" env-default:"10s"`
 }
 
 type Database struct {
+	Driver   string `yaml:"driver" env:"DATABASE_DRIVER" env-default:"postgres"`
+	Path     string `yaml:"path" env:"DATABASE_PATH"`
 	DBName   string `yaml:"dbname" env:"DATABASE_NAME"`
 	User     string `yaml:"user" env:"DATABASE_USER"`
 	Password string `yaml:"password" env:"DATABASE_PASSWORD"`
@@ -26,10 +161,26 @@ type Database struct {
 	SSLMode  string `yaml:"sslmode" env:"DATABASE_SSLMODE"`
 }
 
+// SQLiteDSN builds the connection string for the SQLite database file at Path.
+// Foreign keys are enforced and times are stored in a sortable text format.
+func (d *Database) SQLiteDSN() string {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Set("_time_format", "sqlite")
+
+	return "file:" + d.Path + "?" + q.Encode()
+}
+
 type HTTPServer struct {
-	Address     string        `yaml:"address" env-default:"localhost:8080"`
-	Timeout     time.Duration `yaml:"timeout"`
-	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	Address         string        `yaml:"address" env-default:"localhost:8080"`
+	Timeout         time.Duration `yaml:"timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	// TrustedProxies are the addresses or CIDR ranges of the reverse proxies
+	// whose X-Forwarded-For and X-Real-IP headers are trusted.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 func MustLoad() *Config {
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	ScopeApplicationsRead  = "applications:read"
+	ScopeApplicationsWrite = "applications:write"
+)
+
+// APIKeyScopes are the scopes an API key can be granted.
+var APIKeyScopes = []string{ScopeApplicationsRead, ScopeApplicationsWrite}
+
+// APIKey is a long-lived credential of a user for scripts and integrations.
+// Only the hash of the key is stored, Prefix is kept to tell the keys apart.
+type APIKey struct {
+	ID     int64    `json:"id"`
+	UserID int64    `json:"-"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	// LastUsedAt is nil until the key authenticates a request.
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Created    time.Time  `json:"created"`
+}
@@ -2,11 +2,24 @@ package models
 
 import "time"
 
+// Well-known phase names. Handlers only accept these values.
+const (
+	PhaseApplied   = "applied"
+	PhaseScreening = "screening"
+	PhaseInterview = "interview"
+	PhaseOnsite    = "onsite"
+	PhaseOffer     = "offer"
+	PhaseAccepted  = "accepted"
+	PhaseRejected  = "rejected"
+	PhaseWithdrawn = "withdrawn"
+)
+
 type ApplicationPhase struct {
 	ID            int64     `json:"id" db:"id"`
 	Name          string    `json:"name" db:"name"`
 	Date          time.Time `json:"date" db:"date"`
 	Created       time.Time `json:"created" db:"created"`
 	Notes         string    `json:"notes" db:"notes"`
+	Position      int       `json:"position" db:"position"`
 	ApplicationID int64     `json:"application_id" db:"application_id"`
 }
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Fields applications can be sorted by.
+const (
+	ApplicationSortCompanyName   = "company_name"
+	ApplicationSortPosition      = "position"
+	ApplicationSortOfferedSalary = "offered_salary"
+	ApplicationSortCreated       = "created"
+	ApplicationSortLastModified  = "last_modified"
+)
+
+// SortOrder is a single field of a multi-field sort.
+type SortOrder struct {
+	Field string
+	Desc  bool
+}
+
+// ApplicationFilter narrows down application listings. Zero values mean "no filter".
+// Salary bounds are inclusive, time ranges include their lower bound and exclude the upper one.
+type ApplicationFilter struct {
+	// CompanyName and Position match case-insensitive substrings.
+	CompanyName  string
+	Position     string
+	MinSalary    *int
+	MaxSalary    *int
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	ModifiedFrom *time.Time
+	ModifiedTo   *time.Time
+	// Phase matches the name of the latest phase of the application timeline.
+	Phase string
+}
+
+// ApplicationQuery describes a page of applications.
+// Cursor is the opaque NextCursor of the previous page, empty for the first one.
+type ApplicationQuery struct {
+	Filter ApplicationFilter
+	Sort   []SortOrder
+	Cursor string
+	Limit  int
+}
+
+// ApplicationPage is a page of applications. NextCursor is empty on the last page.
+type ApplicationPage struct {
+	Applications []Application
+	NextCursor   string
+}
+
+// ApplicationSearchHit is an application found by full-text search.
+// Snippet is an HTML-escaped fragment of the application text with matches wrapped in <mark> tags.
+type ApplicationSearchHit struct {
+	Application
+	Rank    float64 `json:"rank" db:"rank"`
+	Snippet string  `json:"snippet" db:"snippet"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// UserIdentity links a user to the account at an external identity provider.
+type UserIdentity struct {
+	Provider string `db:"provider"`
+	// Subject identifies the account at the provider.
+	Subject string `db:"subject"`
+	UserID  int64  `db:"user_id"`
+	// Email is the address the provider reported when the identity was linked.
+	Email string `db:"email"`
+}
+
+// OIDCState is what an external login keeps between the redirect to the provider and the callback.
+type OIDCState struct {
+	Provider string
+	State    string
+	Nonce    string
+	// Verifier is the PKCE code verifier.
+	Verifier string
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// LoginAttempts are the recent failed logins of an account or a client address.
+type LoginAttempts struct {
+	// Failures also counts the logins that are still being checked.
+	Failures int `db:"failures"`
+	// LockedUntil is set while logins are locked out.
+	LockedUntil *time.Time `db:"locked_until"`
+}
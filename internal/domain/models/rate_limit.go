@@ -0,0 +1,91 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimit allows Requests per Period on average and bursts of up to Burst requests.
+// Burst defaults to Requests.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Capacity is the number of tokens of a full bucket.
+func (l RateLimit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Requests
+}
+
+// refillRate is the number of tokens added per second.
+func (l RateLimit) refillRate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// TokenBucket is the state of a rate limited key. Every request takes a token,
+// tokens are refilled continuously at the rate of the limit.
+type TokenBucket struct {
+	Tokens  float64   `db:"tokens"`
+	Updated time.Time `db:"updated"`
+}
+
+// NewTokenBucket returns a full bucket.
+func NewTokenBucket(limit RateLimit, now time.Time) TokenBucket {
+	return TokenBucket{Tokens: float64(limit.Capacity()), Updated: now}
+}
+
+// RateLimitResult is the outcome of a request against a rate limit.
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed. Zero if this one was.
+	RetryAfter time.Duration
+}
+
+// Take refills the bucket up to now and takes a token if there is one.
+// Returns the new state of the bucket.
+func (b TokenBucket) Take(limit RateLimit, now time.Time) (TokenBucket, RateLimitResult) {
+	capacity := float64(limit.Capacity())
+	rate := limit.refillRate()
+
+	elapsed := max(now.Sub(b.Updated).Seconds(), 0)
+	tokens := min(capacity, b.Tokens+elapsed*rate)
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+
+	next := TokenBucket{Tokens: tokens, Updated: now}
+
+	return next, next.Result(limit, allowed)
+}
+
+// Result describes the request that left the bucket in this state.
+func (b TokenBucket) Result(limit RateLimit, allowed bool) RateLimitResult {
+	rate := limit.refillRate()
+
+	res := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit.Capacity(),
+		Remaining: int(math.Floor(b.Tokens)),
+		Reset:     seconds((float64(limit.Capacity()) - b.Tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - b.Tokens) / rate)
+	}
+
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Session is a login of the user on a device: the token family its refresh tokens
+// rotate in. It ends when the family is revoked or its last refresh token expires.
+type Session struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    int64     `json:"-" db:"user_id"`
+	UserAgent string    `json:"user_agent" db:"user_agent"`
+	IP        string    `json:"ip" db:"ip"`
+	Created   time.Time `json:"created" db:"created"`
+	// LastUsedAt is the last time a refresh token of the session was issued.
+	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at"`
+	// Current is set on the session of the request listing the sessions.
+	Current bool `json:"current" db:"-"`
+}
+
+// Client is the device a session is started or used from.
+type Client struct {
+	UserAgent string
+	IP        string
+}
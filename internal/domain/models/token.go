@@ -1,6 +1,38 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 type Tokens struct {
 	Access  string
 	Refresh string
+	// MFA is set instead of Access and Refresh when the login needs a second factor.
+	// It is exchanged for the token pair together with the code.
+	MFA string
+}
+
+// TokenClaims are the claims extracted from a validated token.
+type TokenClaims struct {
+	UserID int64
+	// TokenID is the unique jti of the token.
+	TokenID string
+	// FamilyID groups all tokens issued by rotating refresh tokens of a single login.
+	FamilyID  string
+	ExpiresAt time.Time
+	// APIKeyID is set instead of the token fields when the request is authenticated with an API key.
+	APIKeyID int64
+	// Scopes are the scopes of the API key.
+	Scopes []string
+}
+
+// HasScope reports whether the credential grants the scope.
+// Access tokens grant every scope, API keys only the ones they were created with.
+func (c TokenClaims) HasScope(scope string) bool {
+	if c.APIKeyID == 0 {
+		return true
+	}
+
+	return slices.Contains(c.Scopes, scope)
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TOTP is the authenticator app enrollment of a user. It is pending until ConfirmedAt is set.
+type TOTP struct {
+	UserID      int64      `db:"user_id"`
+	Secret      string     `db:"secret"`
+	ConfirmedAt *time.Time `db:"confirmed_at"`
+	// LastStep is the last time step a code was accepted for, codes can't be used twice.
+	LastStep int64 `db:"last_step"`
+}
+
+// TOTPSetup is what an authenticator app is enrolled with.
+// URI is usually shown as a QR code, Secret is for manual entry.
+type TOTPSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
@@ -1,8 +1,21 @@
 package models
 
+import "time"
+
 type User struct {
-	ID             int64
-	HashedPassword string
-	Email          string
-	Name           string
+	ID             int64  `json:"id"`
+	HashedPassword string `json:"-"`
+	Email          string `json:"email"`
+	Name           string `json:"name"`
+	// Locale is the BCP 47 language tag emails to the user are written in.
+	Locale string `json:"locale"`
+	// VerifiedAt is nil until the user confirms the email address.
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+// UserUpdate holds the profile fields to change. Nil fields are left as they are.
+type UserUpdate struct {
+	Email  *string
+	Name   *string
+	Locale *string
 }
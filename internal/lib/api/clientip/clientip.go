@@ -0,0 +1,17 @@
+package clientip
+
+import (
+	"net"
+	"net/http"
+)
+
+// FromRequest returns the address of the client. Behind the realip middleware
+// RemoteAddr is the client address reported by a trusted proxy, without a port.
+func FromRequest(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -11,6 +11,19 @@ type Response struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// Paginated is a response carrying one page of a list.
+type Paginated struct {
+	Response
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination tells the client how to fetch the next page.
+// NextCursor is empty on the last page.
+type Pagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
 const (
 	StatusOK    = "OK"
 	StatusError = "Error"
@@ -20,6 +33,16 @@ func OK() Response {
 	return Response{Status: StatusOK}
 }
 
+func OKPage(nextCursor string) Paginated {
+	return Paginated{
+		Response: OK(),
+		Pagination: Pagination{
+			NextCursor: nextCursor,
+			HasMore:    nextCursor != "",
+		},
+	}
+}
+
 func Error(msg string) Response {
 	return Response{
 		Status: StatusError,
@@ -36,8 +59,18 @@ func ValidationError(errs validator.ValidationErrors) Response {
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is required", err.Field()))
 		case "min":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at least %v characters long", err.Field(), err.Param()))
+		case "max":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at most %v characters long", err.Field(), err.Param()))
 		case "email":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be a valid email address", err.Field()))
+		case "url":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be a valid URL", err.Field()))
+		case "oneof":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be one of: %s", err.Field(), err.Param()))
+		case "gte":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be greater than or equal to %v", err.Field(), err.Param()))
+		case "lte":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be less than or equal to %v", err.Field(), err.Param()))
 		default:
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
 		}
@@ -0,0 +1,109 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// jwkSet is a JSON Web Key Set, RFC 7517.
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+// keys returns the signature keys of the set by key id.
+// Encryption keys and keys of unsupported types are skipped.
+func (s jwkSet) keys() map[string]any {
+	keys := make(map[string]any, len(s.Keys))
+
+	for _, k := range s.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		if key := k.publicKey(); key != nil {
+			keys[k.KeyID] = key
+		}
+	}
+
+	return keys
+}
+
+func (k jwk) publicKey() any {
+	switch k.KeyType {
+	case "RSA":
+		n, ok := decodeInt(k.N)
+		if !ok {
+			return nil
+		}
+
+		e, ok := decodeInt(k.E)
+		if !ok || !e.IsInt64() {
+			return nil
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil
+		}
+
+		x, ok := decodeInt(k.X)
+		if !ok {
+			return nil
+		}
+
+		y, ok := decodeInt(k.Y)
+		if !ok {
+			return nil
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil
+		}
+
+		return ed25519.PublicKey(x)
+	default:
+		return nil
+	}
+}
+
+func decodeInt(s string) (*big.Int, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, false
+	}
+
+	return new(big.Int).SetBytes(b), true
+}
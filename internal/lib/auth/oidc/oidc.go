@@ -0,0 +1,364 @@
+// Package oidc implements the OpenID Connect authorization code flow for relying parties:
+// provider discovery, PKCE, state and nonce, and validation of the id token against the provider keys.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrRejected is returned when the provider refuses to exchange the code,
+	// for example because it expired or the PKCE verifier doesn't match.
+	ErrRejected       = errors.New("rejected by the identity provider")
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+const (
+	randomTokenSize = 32
+	maxResponseSize = 1 << 20
+	// keysRefreshInterval limits how often the keys are refetched when a token is signed with an unknown key.
+	keysRefreshInterval = time.Minute
+	clockSkew           = time.Minute
+)
+
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Config is the registration of the application at the provider.
+type Config struct {
+	// Issuer is the issuer identifier, the provider metadata is discovered from it.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes are requested in addition to openid.
+	Scopes []string
+}
+
+// Identity is the end user authenticated by the provider.
+type Identity struct {
+	// Subject identifies the user at the provider. Unlike the email, it never changes.
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp"`
+	Email           string `json:"email"`
+	EmailVerified   bool   `json:"email_verified"`
+	Name            string `json:"name"`
+}
+
+// Provider is an OpenID provider. The metadata and keys are fetched on first use and cached,
+// so the application starts even if the provider is unavailable.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *metadata
+	keys          map[string]any
+	keysRefreshed time.Time
+}
+
+// NewProvider returns the provider of cfg. If client is nil, http.DefaultClient is used.
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Provider{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// RandomToken returns a random url-safe string fit for the state, the nonce and the PKCE verifier.
+func RandomToken() (string, error) {
+	const op = "oidc.RandomToken"
+
+	b := make([]byte, randomTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge returns the S256 PKCE challenge of the verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthURL returns the authorization endpoint URL the user is redirected to.
+// The state, nonce and verifier must be kept until the callback.
+func (p *Provider) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	const op = "oidc.AuthURL"
+
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(append([]string{"openid"}, p.cfg.Scopes...), " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", Challenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange redeems the authorization code and returns the identity from the validated id token.
+// Returns ErrRejected if the provider refuses the code and ErrInvalidIDToken if the token doesn't validate.
+func (p *Provider) Exchange(ctx context.Context, code, nonce, verifier string) (Identity, error) {
+	const op = "oidc.Exchange"
+
+	md, err := p.discover(ctx)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", verifier)
+
+	// client_secret_basic is the default when the provider doesn't list the methods.
+	basicAuth := p.cfg.ClientSecret != "" &&
+		(len(md.TokenAuthMethods) == 0 || slices.Contains(md.TokenAuthMethods, "client_secret_basic"))
+	if !basicAuth {
+		form.Set("client_id", p.cfg.ClientID)
+		if p.cfg.ClientSecret != "" {
+			form.Set("client_secret", p.cfg.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(&body); err != nil {
+		return Identity{}, fmt.Errorf("%s: token endpoint returned %s: %w", op, res.Status, err)
+	}
+
+	if body.Error != "" {
+		return Identity{}, fmt.Errorf("%s: %w: %s: %s", op, ErrRejected, body.Error, body.ErrorDescription)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return Identity{}, fmt.Errorf("%s: token endpoint returned %s", op, res.Status)
+	}
+
+	if body.IDToken == "" {
+		return Identity{}, fmt.Errorf("%s: %w: missing in token response", op, ErrInvalidIDToken)
+	}
+
+	identity, err := p.verify(ctx, md, body.IDToken, nonce)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identity, nil
+}
+
+func (p *Provider) verify(ctx context.Context, md *metadata, rawToken, nonce string) (Identity, error) {
+	claims := &idTokenClaims{}
+
+	// The key lookup can fail because of the network, it is reported as is
+	// and not as an invalid token.
+	var keyErr error
+	_, err := jwt.ParseWithClaims(
+		rawToken,
+		claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+
+			key, err := p.key(ctx, md, kid)
+			if err != nil {
+				keyErr = err
+			}
+
+			return key, err
+		},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(md.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if keyErr != nil {
+		return Identity{}, keyErr
+	}
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if claims.Nonce != nonce {
+		return Identity{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return Identity{}, fmt.Errorf("%w: issued to %q", ErrInvalidIDToken, claims.AuthorizedParty)
+	}
+
+	if claims.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover returns the provider metadata, fetching it on first use.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var md metadata
+	if err := p.get(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", &md); err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+
+	if md.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery: issuer %q doesn't match %q", md.Issuer, p.cfg.Issuer)
+	}
+
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("discovery: metadata is missing endpoints")
+	}
+
+	p.metadata = &md
+
+	return p.metadata, nil
+}
+
+// key returns the provider key with the id. The keys are refetched when the id is unknown,
+// providers rotate them without notice. A token without an id is accepted if the provider has a single key.
+func (p *Provider) key(ctx context.Context, md *metadata, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := lookupKey(p.keys, kid); ok {
+		return key, nil
+	}
+
+	if p.keys != nil && time.Since(p.keysRefreshed) < keysRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidIDToken, kid)
+	}
+
+	var set jwkSet
+	if err := p.get(ctx, md.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("keys: %w", err)
+	}
+
+	p.keys = set.keys()
+	p.keysRefreshed = time.Now()
+
+	if key, ok := lookupKey(p.keys, kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidIDToken, kid)
+}
+
+func lookupKey(keys map[string]any, kid string) (any, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, ok := keys[kid]
+
+	return key, ok
+}
+
+func (p *Provider) get(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, res.Status)
+	}
+
+	return json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(v)
+}
@@ -0,0 +1,127 @@
+package oidc_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/oidc"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+)
+
+const redirectURL = "https://tracker.example.com/api/auth/oidc/test/callback"
+
+func newProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	t.Helper()
+
+	srv, err := oidctest.NewServer("tracker", "s3cret/+")
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+
+	p := oidc.NewProvider(oidc.Config{
+		Issuer:       srv.Issuer(),
+		ClientID:     srv.ClientID,
+		ClientSecret: srv.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"email", "profile"},
+	}, srv.Client())
+
+	return srv, p
+}
+
+// authorize starts a login and returns the state, nonce and verifier with the code from the callback.
+func authorize(t *testing.T, srv *oidctest.Server, p *oidc.Provider) (string, string, string, string) {
+	t.Helper()
+
+	state, err := oidc.RandomToken()
+	require.NoError(t, err)
+	nonce, err := oidc.RandomToken()
+	require.NoError(t, err)
+	verifier, err := oidc.RandomToken()
+	require.NoError(t, err)
+
+	authURL, err := p.AuthURL(context.Background(), state, nonce, verifier)
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+	assert.Equal(t, oidc.Challenge(verifier), u.Query().Get("code_challenge"))
+	assert.Empty(t, u.Query().Get("code_verifier"))
+
+	callback, err := srv.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, state, callback.Query().Get("state"))
+
+	return state, nonce, verifier, callback.Query().Get("code")
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	ctx := context.Background()
+	srv, p := newProvider(t)
+
+	srv.SetUser(oidctest.User{
+		Subject:       "42",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane",
+	})
+
+	_, nonce, verifier, code := authorize(t, srv, p)
+
+	identity, err := p.Exchange(ctx, code, nonce, verifier)
+	require.NoError(t, err)
+	assert.Equal(t, oidc.Identity{
+		Subject:       "42",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane",
+	}, identity)
+
+	// Codes are single use.
+	_, err = p.Exchange(ctx, code, nonce, verifier)
+	assert.ErrorIs(t, err, oidc.ErrRejected)
+}
+
+func TestProvider_Exchange_PKCE(t *testing.T) {
+	srv, p := newProvider(t)
+
+	_, nonce, _, code := authorize(t, srv, p)
+
+	other, err := oidc.RandomToken()
+	require.NoError(t, err)
+
+	_, err = p.Exchange(context.Background(), code, nonce, other)
+	assert.ErrorIs(t, err, oidc.ErrRejected)
+}
+
+func TestProvider_Exchange_Nonce(t *testing.T) {
+	srv, p := newProvider(t)
+
+	_, _, verifier, code := authorize(t, srv, p)
+
+	_, err := p.Exchange(context.Background(), code, "replayed", verifier)
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
+
+func TestProvider_Discovery(t *testing.T) {
+	srv, _ := newProvider(t)
+
+	p := oidc.NewProvider(oidc.Config{
+		Issuer:   srv.Issuer() + "/other",
+		ClientID: srv.ClientID,
+	}, srv.Client())
+
+	_, err := p.AuthURL(context.Background(), "state", "nonce", "verifier")
+	assert.Error(t, err)
+}
+
+func TestChallenge(t *testing.T) {
+	// RFC 7636 appendix B.
+	assert.Equal(
+		t,
+		"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		oidc.Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"),
+	)
+}
@@ -0,0 +1,154 @@
+package password_hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+var (
+	ErrMismatchedPassword = errors.New("hashed password is not the hash of the given password")
+	ErrInvalidHash        = errors.New("invalid password hash")
+	ErrInvalidParams      = errors.New("invalid argon2id parameters")
+)
+
+// Argon2idParams are the cost parameters of Argon2id. Memory is in KiB.
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams follow the OWASP recommendation of 19 MiB, 2 iterations and no parallelism.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2idPasswordHasher hashes passwords with Argon2id into PHC strings such as
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>. Unlike bcrypt it uses the whole password.
+type Argon2idPasswordHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idPasswordHasher returns ErrInvalidParams if Argon2id can't hash with params:
+// it needs at least one iteration and one lane, and 8 KiB of memory per lane.
+func NewArgon2idPasswordHasher(params Argon2idParams) (*Argon2idPasswordHasher, error) {
+	const op = "lib.auth.password_hasher.NewArgon2idPasswordHasher"
+
+	switch {
+	case params.Iterations < 1:
+		return nil, fmt.Errorf("%s: %w: iterations must be at least 1", op, ErrInvalidParams)
+	case params.Parallelism < 1:
+		return nil, fmt.Errorf("%s: %w: parallelism must be at least 1", op, ErrInvalidParams)
+	case params.Memory < 8*uint32(params.Parallelism):
+		return nil, fmt.Errorf("%s: %w: memory must be at least 8 KiB per lane", op, ErrInvalidParams)
+	case params.SaltLength == 0 || params.KeyLength == 0:
+		return nil, fmt.Errorf("%s: %w: salt and key length must be positive", op, ErrInvalidParams)
+	}
+
+	return &Argon2idPasswordHasher{params: params}, nil
+}
+
+func (h *Argon2idPasswordHasher) Generate(password string) (string, error) {
+	const op = "lib.auth.password_hasher.Argon2id.Generate"
+
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		h.params.Memory,
+		h.params.Iterations,
+		h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Compare hashes the password with the parameters of the hash, not the ones of h.
+func (h *Argon2idPasswordHasher) Compare(hashedPassword string, password string) error {
+	params, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+
+	return nil
+}
+
+// Matches reports whether the hash is an Argon2id hash.
+func (h *Argon2idPasswordHasher) Matches(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, argon2idPrefix)
+}
+
+// NeedsRehash reports whether the hash was made with less memory, fewer iterations or
+// fewer lanes than h uses, or with another salt or key length. Stronger hashes are
+// left alone, so lowering the parameters doesn't weaken stored hashes.
+func (h *Argon2idPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	params, _, _, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory < h.params.Memory ||
+		params.Iterations < h.params.Iterations ||
+		params.Parallelism < h.params.Parallelism ||
+		params.SaltLength != h.params.SaltLength ||
+		params.KeyLength != h.params.KeyLength
+}
+
+func decodeArgon2id(hashedPassword string) (Argon2idParams, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2idParams{}, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2idParams{}, nil, nil, ErrInvalidHash
+	}
+
+	var params Argon2idParams
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil || params.Iterations == 0 || params.Parallelism == 0 {
+		return Argon2idParams{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2idParams{}, nil, nil, ErrInvalidHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,73 @@
+package password_hasher_test
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestArgon2idPasswordHasher(t *testing.T) {
+	h, err := password_hasher.NewArgon2idPasswordHasher(password_hasher.DefaultArgon2idParams)
+	require.NoError(t, err)
+	password := generatePassword()
+
+	hashedPassword, err := h.Generate(password)
+	require.NoError(t, err)
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=19456,t=2,p=1\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`, hashedPassword)
+	assert.True(t, h.Matches(hashedPassword))
+	assert.False(t, h.NeedsRehash(hashedPassword))
+
+	other, err := h.Generate(password)
+	require.NoError(t, err)
+	assert.NotEqual(t, hashedPassword, other)
+
+	assert.ErrorIs(t, h.Compare(hashedPassword, "invalid_password"), password_hasher.ErrMismatchedPassword)
+	assert.NoError(t, h.Compare(hashedPassword, password))
+
+	// Bytes past 72 count, unlike with bcrypt.
+	long := strings.Repeat("a", 72)
+	hashedLong, err := h.Generate(long + "1")
+	require.NoError(t, err)
+	assert.Error(t, h.Compare(hashedLong, long+"2"))
+
+	stronger := password_hasher.DefaultArgon2idParams
+	stronger.Iterations++
+	strongerHasher, err := password_hasher.NewArgon2idPasswordHasher(stronger)
+	require.NoError(t, err)
+	assert.True(t, strongerHasher.NeedsRehash(hashedPassword))
+
+	// Lowering the parameters leaves stronger hashes alone.
+	strongerHash, err := strongerHasher.Generate(password)
+	require.NoError(t, err)
+	assert.False(t, h.NeedsRehash(strongerHash))
+
+	longer := password_hasher.DefaultArgon2idParams
+	longer.KeyLength *= 2
+	longerHasher, err := password_hasher.NewArgon2idPasswordHasher(longer)
+	require.NoError(t, err)
+	assert.True(t, longerHasher.NeedsRehash(hashedPassword))
+
+	for _, invalid := range []string{"", "$argon2id$v=19$m=19456,t=2,p=1$c2FsdA", "$argon2id$v=16$m=1,t=1,p=1$c2FsdA$aGFzaA"} {
+		assert.ErrorIs(t, h.Compare(invalid, password), password_hasher.ErrInvalidHash)
+	}
+}
+
+func TestNewArgon2idPasswordHasher_InvalidParams(t *testing.T) {
+	for name, modify := range map[string]func(p *password_hasher.Argon2idParams){
+		"no iterations":  func(p *password_hasher.Argon2idParams) { p.Iterations = 0 },
+		"no parallelism": func(p *password_hasher.Argon2idParams) { p.Parallelism = 0 },
+		"little memory":  func(p *password_hasher.Argon2idParams) { p.Memory, p.Parallelism = 15, 2 },
+		"no salt":        func(p *password_hasher.Argon2idParams) { p.SaltLength = 0 },
+		"no key":         func(p *password_hasher.Argon2idParams) { p.KeyLength = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			params := password_hasher.DefaultArgon2idParams
+			modify(&params)
+
+			_, err := password_hasher.NewArgon2idPasswordHasher(params)
+			assert.ErrorIs(t, err, password_hasher.ErrInvalidParams)
+		})
+	}
+}
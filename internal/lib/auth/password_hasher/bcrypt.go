@@ -3,18 +3,23 @@ package password_hasher
 import (
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
-type BcryptPasswordHasher struct{}
+// BcryptPasswordHasher only uses the first 72 bytes of passwords, it refuses
+// to hash longer ones. Prefer Argon2idPasswordHasher for new hashes.
+type BcryptPasswordHasher struct {
+	cost int
+}
 
 func NewBcryptPasswordHasher() *BcryptPasswordHasher {
-	return &BcryptPasswordHasher{}
+	return &BcryptPasswordHasher{cost: bcrypt.DefaultCost}
 }
 
 func (h *BcryptPasswordHasher) Generate(password string) (string, error) {
 	const op = "lib.auth.password_hasher.Generate"
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -25,3 +30,24 @@ func (h *BcryptPasswordHasher) Generate(password string) (string, error) {
 func (h *BcryptPasswordHasher) Compare(hashedPassword string, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+// Matches reports whether the hash is a bcrypt hash.
+func (h *BcryptPasswordHasher) Matches(hashedPassword string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(hashedPassword, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NeedsRehash reports whether the hash has a lower cost than the one of h.
+func (h *BcryptPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return true
+	}
+
+	return cost < h.cost
+}
@@ -0,0 +1,69 @@
+package password_hasher
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+type formatHasher interface {
+	Generate(password string) (string, error)
+	Compare(hashedPassword string, password string) error
+	Matches(hashedPassword string) bool
+	NeedsRehash(hashedPassword string) bool
+}
+
+// MultiPasswordHasher hashes new passwords with the preferred hasher and compares
+// stored hashes with the hasher of their format, so that hashes made by legacy
+// hashers keep working until they are upgraded.
+type MultiPasswordHasher struct {
+	preferred formatHasher
+	legacy    []formatHasher
+}
+
+func NewMultiPasswordHasher(preferred formatHasher, legacy ...formatHasher) *MultiPasswordHasher {
+	return &MultiPasswordHasher{
+		preferred: preferred,
+		legacy:    legacy,
+	}
+}
+
+func (h *MultiPasswordHasher) Generate(password string) (string, error) {
+	return h.preferred.Generate(password)
+}
+
+func (h *MultiPasswordHasher) Compare(hashedPassword string, password string) error {
+	const op = "lib.auth.password_hasher.Multi.Compare"
+
+	hasher, ok := h.hasher(hashedPassword)
+	if !ok {
+		return fmt.Errorf("%s: %w", op, ErrUnknownHashFormat)
+	}
+
+	return hasher.Compare(hashedPassword, password)
+}
+
+// NeedsRehash reports whether the hash was made by a legacy hasher
+// or with weaker parameters than the preferred hasher uses.
+func (h *MultiPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	if !h.preferred.Matches(hashedPassword) {
+		return true
+	}
+
+	return h.preferred.NeedsRehash(hashedPassword)
+}
+
+func (h *MultiPasswordHasher) hasher(hashedPassword string) (formatHasher, bool) {
+	if h.preferred.Matches(hashedPassword) {
+		return h.preferred, true
+	}
+
+	for _, hasher := range h.legacy {
+		if hasher.Matches(hashedPassword) {
+			return hasher, true
+		}
+	}
+
+	return nil, false
+}
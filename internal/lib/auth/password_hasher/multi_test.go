@@ -0,0 +1,30 @@
+package password_hasher_test
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMultiPasswordHasher(t *testing.T) {
+	argon2id, err := password_hasher.NewArgon2idPasswordHasher(password_hasher.DefaultArgon2idParams)
+	require.NoError(t, err)
+	bcrypt := password_hasher.NewBcryptPasswordHasher()
+	h := password_hasher.NewMultiPasswordHasher(argon2id, bcrypt)
+	password := generatePassword()
+
+	hashedPassword, err := h.Generate(password)
+	require.NoError(t, err)
+	assert.True(t, argon2id.Matches(hashedPassword))
+	assert.NoError(t, h.Compare(hashedPassword, password))
+	assert.False(t, h.NeedsRehash(hashedPassword))
+
+	legacy, err := bcrypt.Generate(password)
+	require.NoError(t, err)
+	assert.NoError(t, h.Compare(legacy, password))
+	assert.Error(t, h.Compare(legacy, "invalid_password"))
+	assert.True(t, h.NeedsRehash(legacy))
+
+	assert.ErrorIs(t, h.Compare("plain", password), password_hasher.ErrUnknownHashFormat)
+}
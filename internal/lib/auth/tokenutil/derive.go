@@ -0,0 +1,16 @@
+package tokenutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// DeriveSecret derives the signing secret of purpose from secret, so that
+// tokens of different kinds can't be exchanged for one another.
+func DeriveSecret(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,7 +1,6 @@
 package tokenutil
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"github.com/diproducts/application-tracker-go/internal/domain/models"
@@ -11,18 +10,22 @@ import (
 	"time"
 )
 
-// TODO: decide what to do with token repository
-type tokenRepository interface {
-	BlacklistTokenByID(ctx context.Context, userID int64, tokenID string, expiry time.Time) error
-	IsBlacklisted(ctx context.Context, tokenID string) (bool, error)
-}
-
 var (
 	ErrInvalidToken = errors.New("invalid token")
 )
 
+// Claims are the JWT claims of both access and refresh tokens.
+type Claims struct {
+	jwt.RegisteredClaims
+	FamilyID string `json:"fam,omitempty"`
+}
+
+// JWTTokenManager signs access tokens with AccessKeys when set, so that other services
+// can verify them with the public keys. Otherwise they are signed with AccessSecret.
+// Refresh tokens are only read by this service and always use RefreshSecret.
 type JWTTokenManager struct {
 	AccessSecret  string
+	AccessKeys    *KeySet
 	RefreshSecret string
 	AccessExpiry  time.Duration
 	RefreshExpiry time.Duration
@@ -42,6 +45,21 @@ func NewJWTTokenManager(
 	}
 }
 
+// NewKeySetJWTTokenManager creates a manager signing access tokens with the keys.
+func NewKeySetJWTTokenManager(
+	accessKeys *KeySet,
+	refreshSecret string,
+	accessExpiry time.Duration,
+	refreshExpiry time.Duration,
+) *JWTTokenManager {
+	return &JWTTokenManager{
+		AccessKeys:    accessKeys,
+		RefreshSecret: refreshSecret,
+		AccessExpiry:  accessExpiry,
+		RefreshExpiry: refreshExpiry,
+	}
+}
+
 // CreateUserAccessToken creates a new access token for the user.
 func (tm *JWTTokenManager) CreateUserAccessToken(user *models.User) (string, error) {
 	return tm.createAccessToken(user.ID)
@@ -52,39 +70,75 @@ func (tm *JWTTokenManager) CreateUserRefreshToken(user *models.User) (string, er
 	return tm.createRefreshToken(user.ID)
 }
 
+// CreateTokenPair creates access and refresh tokens for the user
+// that belong to the given token family.
+func (tm *JWTTokenManager) CreateTokenPair(user *models.User, familyID string) (models.Tokens, error) {
+	accessToken, err := tm.createToken(user.ID, familyID, tm.AccessExpiry, tm.signAccess)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	refreshToken, err := tm.createToken(user.ID, familyID, tm.RefreshExpiry, tm.signRefresh)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	return models.Tokens{
+		Access:  accessToken,
+		Refresh: refreshToken,
+	}, nil
+}
+
+// ParseAccessToken validates the access token and returns its claims.
+func (tm *JWTTokenManager) ParseAccessToken(tokenStr string) (models.TokenClaims, error) {
+	return tm.parseTokenClaims(tokenStr, tm.accessKeyFunc)
+}
+
+// ParseRefreshToken validates the refresh token and returns its claims.
+func (tm *JWTTokenManager) ParseRefreshToken(tokenStr string) (models.TokenClaims, error) {
+	return tm.parseTokenClaims(tokenStr, tm.refreshKeyFunc)
+}
+
 // ExtractUserIDFromAccessToken extracts user id from access token.
 func (tm *JWTTokenManager) ExtractUserIDFromAccessToken(tokenStr string) (int64, error) {
-	return tm.extractUserIDFromToken(tokenStr, tm.AccessSecret)
+	return tm.extractUserIDFromToken(tokenStr, tm.accessKeyFunc)
 }
 
 // ExtractUserIDFromRefreshToken extracts user id from refresh token.
 func (tm *JWTTokenManager) ExtractUserIDFromRefreshToken(tokenStr string) (int64, error) {
-	return tm.extractUserIDFromToken(tokenStr, tm.RefreshSecret)
+	return tm.extractUserIDFromToken(tokenStr, tm.refreshKeyFunc)
 }
 
 func (tm *JWTTokenManager) createAccessToken(id int64) (string, error) {
-	return tm.createToken(id, tm.AccessSecret, tm.AccessExpiry)
+	return tm.createToken(id, "", tm.AccessExpiry, tm.signAccess)
 }
 
 func (tm *JWTTokenManager) createRefreshToken(id int64) (string, error) {
-	return tm.createToken(id, tm.RefreshSecret, tm.RefreshExpiry)
+	return tm.createToken(id, "", tm.RefreshExpiry, tm.signRefresh)
 }
 
-func (tm *JWTTokenManager) createToken(id int64, secret string, expiry time.Duration) (string, error) {
+func (tm *JWTTokenManager) createToken(
+	id int64,
+	familyID string,
+	expiry time.Duration,
+	sign func(claims jwt.Claims) (string, error),
+) (string, error) {
 	const op = "tokenutil.createToken"
 
 	now := time.Now()
 	exp := now.Add(expiry)
-	claims := &jwt.RegisteredClaims{
-		ID:        uuid.NewString(),
-		Subject:   strconv.FormatInt(id, 10),
-		ExpiresAt: jwt.NewNumericDate(exp),
-		IssuedAt:  jwt.NewNumericDate(now),
-		NotBefore: jwt.NewNumericDate(now),
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   strconv.FormatInt(id, 10),
+			ExpiresAt: jwt.NewNumericDate(exp),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		FamilyID: familyID,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(secret))
+	signedToken, err := sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -92,44 +146,76 @@ func (tm *JWTTokenManager) createToken(id int64, secret string, expiry time.Dura
 	return signedToken, nil
 }
 
-func (tm *JWTTokenManager) parseToken(tokenStr, secret string, claims jwt.Claims) (*jwt.Token, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+func (tm *JWTTokenManager) signAccess(claims jwt.Claims) (string, error) {
+	if tm.AccessKeys != nil {
+		return tm.AccessKeys.sign(claims)
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(tm.AccessSecret))
+}
+
+func (tm *JWTTokenManager) signRefresh(claims jwt.Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(tm.RefreshSecret))
+}
+
+func (tm *JWTTokenManager) accessKeyFunc(token *jwt.Token) (interface{}, error) {
+	if tm.AccessKeys != nil {
+		return tm.AccessKeys.keyFunc(token)
+	}
+
+	return hmacKeyFunc(tm.AccessSecret)(token)
+}
+
+func (tm *JWTTokenManager) refreshKeyFunc(token *jwt.Token) (interface{}, error) {
+	return hmacKeyFunc(tm.RefreshSecret)(token)
+}
+
+func hmacKeyFunc(secret string) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
 		return []byte(secret), nil
-	})
-
-	return token, err
+	}
 }
 
-func (tm *JWTTokenManager) extractUserIDFromToken(tokenStr, secret string) (int64, error) {
-	idStr, err := tm.extractSubjectFromToken(tokenStr, secret)
+func (tm *JWTTokenManager) extractUserIDFromToken(tokenStr string, keyFunc jwt.Keyfunc) (int64, error) {
+	claims, err := tm.parseTokenClaims(tokenStr, keyFunc)
 	if err != nil {
 		return 0, err
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		return 0, ErrInvalidToken
-	}
-
-	return id, nil
+	return claims.UserID, nil
 }
 
-func (tm *JWTTokenManager) extractSubjectFromToken(tokenStr, secret string) (string, error) {
-	const op = "tokenutil.extractSubjectFromToken"
+func (tm *JWTTokenManager) parseTokenClaims(tokenStr string, keyFunc jwt.Keyfunc) (models.TokenClaims, error) {
+	const op = "tokenutil.parseTokenClaims"
 
-	claims := &jwt.RegisteredClaims{}
-	token, err := tm.parseToken(tokenStr, secret, claims)
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, keyFunc)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		return models.TokenClaims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
 	}
 
 	if !token.Valid {
-		return "", ErrInvalidToken
+		return models.TokenClaims{}, ErrInvalidToken
+	}
+
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return models.TokenClaims{}, ErrInvalidToken
+	}
+
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
 	}
 
-	return claims.Subject, nil
+	return models.TokenClaims{
+		UserID:    id,
+		TokenID:   claims.ID,
+		FamilyID:  claims.FamilyID,
+		ExpiresAt: expiresAt,
+	}, nil
 }
@@ -1,12 +1,20 @@
 package tokenutil_test
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"github.com/diproducts/application-tracker-go/internal/domain/models"
 	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -84,3 +92,295 @@ func parseToken(tokenStr, secret string, claims jwt.Claims) (*jwt.Token, error)
 
 	return token, err
 }
+
+func TestJWTTokenManager_CreateTokenPair(t *testing.T) {
+	tm := tokenutil.NewJWTTokenManager(accessSecret, refreshSecret, accessTTL, refreshTTL)
+	user := models.User{ID: userID}
+	familyID := "test_family"
+	const deltaSeconds = 1
+
+	tokens, err := tm.CreateTokenPair(&user, familyID)
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens.Access)
+	require.NotEmpty(t, tokens.Refresh)
+
+	accessClaims, err := tm.ParseAccessToken(tokens.Access)
+	require.NoError(t, err)
+	refreshClaims, err := tm.ParseRefreshToken(tokens.Refresh)
+	require.NoError(t, err)
+
+	assert.Equal(t, userID, accessClaims.UserID)
+	assert.Equal(t, userID, refreshClaims.UserID)
+	assert.Equal(t, familyID, accessClaims.FamilyID)
+	assert.Equal(t, familyID, refreshClaims.FamilyID)
+	assert.NotEmpty(t, refreshClaims.TokenID)
+	assert.NotEqual(t, accessClaims.TokenID, refreshClaims.TokenID)
+	assert.InDelta(t, time.Now().Add(tm.RefreshExpiry).Unix(), refreshClaims.ExpiresAt.Unix(), deltaSeconds)
+
+	_, err = tm.ParseRefreshToken(tokens.Access)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+	_, err = tm.ParseAccessToken(tokens.Refresh)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+}
+
+func newSigningKey(t *testing.T, id string, rsaKey bool, notBefore, retireAt time.Time) tokenutil.SigningKey {
+	t.Helper()
+
+	var key crypto.Signer
+	if rsaKey {
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		key = k
+	} else {
+		_, k, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		key = k
+	}
+
+	return tokenutil.SigningKey{ID: id, PrivateKey: key, NotBefore: notBefore, RetireAt: retireAt}
+}
+
+func TestKeySetJWTTokenManager(t *testing.T) {
+	now := time.Now()
+	rsaKey := newSigningKey(t, "rsa", true, now.Add(-time.Hour), time.Time{})
+	edKey := newSigningKey(t, "ed", false, now.Add(-time.Minute), time.Time{})
+
+	for _, key := range []tokenutil.SigningKey{rsaKey, edKey} {
+		ks, err := tokenutil.NewKeySet(key)
+		require.NoError(t, err)
+		tm := tokenutil.NewKeySetJWTTokenManager(ks, refreshSecret, accessTTL, refreshTTL)
+
+		tokens, err := tm.CreateTokenPair(&models.User{ID: userID}, "family")
+		require.NoError(t, err)
+
+		claims, err := tm.ParseAccessToken(tokens.Access)
+		require.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+
+		refreshClaims, err := tm.ParseRefreshToken(tokens.Refresh)
+		require.NoError(t, err)
+		assert.Equal(t, userID, refreshClaims.UserID)
+
+		// Other services only need the published key.
+		jwks := ks.JWKS()
+		require.Len(t, jwks.Keys, 1)
+		assert.Equal(t, key.ID, jwks.Keys[0].KeyID)
+
+		token, err := jwt.Parse(tokens.Access, func(token *jwt.Token) (interface{}, error) {
+			assert.Equal(t, key.ID, token.Header["kid"])
+
+			return publicKey(t, jwks.Keys[0]), nil
+		}, jwt.WithValidMethods([]string{jwks.Keys[0].Algorithm}))
+		require.NoError(t, err)
+		assert.True(t, token.Valid)
+	}
+
+	// Access tokens signed with a secret are not accepted anymore.
+	hs := tokenutil.NewJWTTokenManager(accessSecret, refreshSecret, accessTTL, refreshTTL)
+	hsToken, err := hs.CreateUserAccessToken(&models.User{ID: userID})
+	require.NoError(t, err)
+
+	ks, err := tokenutil.NewKeySet(rsaKey)
+	require.NoError(t, err)
+	_, err = tokenutil.NewKeySetJWTTokenManager(ks, refreshSecret, accessTTL, refreshTTL).ParseAccessToken(hsToken)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	now := time.Now()
+	user := models.User{ID: userID}
+
+	old := newSigningKey(t, "2024-01", false, now.Add(-48*time.Hour), time.Time{})
+	current := newSigningKey(t, "2024-02", false, now.Add(-time.Hour), time.Time{})
+	next := newSigningKey(t, "2024-03", false, now.Add(time.Hour), time.Time{})
+
+	before, err := tokenutil.NewKeySet(old)
+	require.NoError(t, err)
+	oldToken, err := tokenutil.NewKeySetJWTTokenManager(before, refreshSecret, accessTTL, refreshTTL).
+		CreateUserAccessToken(&user)
+	require.NoError(t, err)
+
+	ks, err := tokenutil.NewKeySet(old, next, current)
+	require.NoError(t, err)
+	tm := tokenutil.NewKeySetJWTTokenManager(ks, refreshSecret, accessTTL, refreshTTL)
+
+	// The newest key that already started signs, the next one is only published.
+	token, err := tm.CreateUserAccessToken(&user)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, current.ID, parsed.Header["kid"])
+
+	var ids []string
+	for _, k := range ks.JWKS().Keys {
+		ids = append(ids, k.KeyID)
+	}
+	assert.ElementsMatch(t, []string{old.ID, current.ID, next.ID}, ids)
+
+	// Tokens signed before the rotation stay valid.
+	_, err = tm.ParseAccessToken(oldToken)
+	assert.NoError(t, err)
+
+	old.RetireAt = now.Add(-time.Minute)
+	ks, err = tokenutil.NewKeySet(old, current)
+	require.NoError(t, err)
+	tm = tokenutil.NewKeySetJWTTokenManager(ks, refreshSecret, accessTTL, refreshTTL)
+
+	_, err = tm.ParseAccessToken(oldToken)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+	assert.Len(t, ks.JWKS().Keys, 1)
+
+	_, err = tokenutil.NewKeySet(old, next)
+	assert.ErrorIs(t, err, tokenutil.ErrNoSigningKey)
+
+	_, err = tokenutil.NewKeySet(current, current)
+	assert.Error(t, err)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	key, err := tokenutil.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+	require.NoError(t, err)
+	assert.Equal(t, edKey, key)
+
+	pkcs1 := x509.MarshalPKCS1PrivateKey(rsaKey)
+	key, err = tokenutil.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs1}))
+	require.NoError(t, err)
+	assert.True(t, rsaKey.Equal(key))
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = tokenutil.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(weak),
+	}))
+	assert.Error(t, err)
+
+	_, err = tokenutil.ParsePrivateKey([]byte("not a key"))
+	assert.Error(t, err)
+}
+
+// publicKey decodes the public key of the jwk like a verifying service would.
+func publicKey(t *testing.T, k tokenutil.JWK) crypto.PublicKey {
+	t.Helper()
+
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		require.NoError(t, err)
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		require.NoError(t, err)
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		require.NoError(t, err)
+
+		return ed25519.PublicKey(x)
+	default:
+		t.Fatalf("unexpected key type %q", k.KeyType)
+		return nil
+	}
+}
+
+func TestVerificationTokenManager(t *testing.T) {
+	tm := tokenutil.NewVerificationTokenManager(accessSecret, time.Hour)
+	user := models.User{ID: userID, Email: "user@example.com"}
+
+	tokenStr, err := tm.CreateVerificationToken(&user)
+	require.NoError(t, err)
+
+	gotID, gotEmail, err := tm.ParseVerificationToken(tokenStr)
+	require.NoError(t, err)
+	assert.Equal(t, userID, gotID)
+	assert.Equal(t, user.Email, gotEmail)
+
+	// Access tokens signed with the same secret are not verification tokens.
+	access := tokenutil.NewJWTTokenManager(accessSecret, refreshSecret, accessTTL, refreshTTL)
+	accessToken, err := access.CreateUserAccessToken(&user)
+	require.NoError(t, err)
+
+	_, _, err = tm.ParseVerificationToken(accessToken)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+
+	expired := tokenutil.NewVerificationTokenManager(accessSecret, -time.Minute)
+	tokenStr, err = expired.CreateVerificationToken(&user)
+	require.NoError(t, err)
+
+	_, _, err = tm.ParseVerificationToken(tokenStr)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+}
+
+func TestMFATokenManager(t *testing.T) {
+	tm := tokenutil.NewMFATokenManager(accessSecret, time.Minute)
+
+	tokenStr, err := tm.CreateMFAToken(userID)
+	require.NoError(t, err)
+
+	gotID, err := tm.ParseMFAToken(tokenStr)
+	require.NoError(t, err)
+	assert.Equal(t, userID, gotID)
+
+	// Verification tokens signed with the same secret are not mfa tokens.
+	verification := tokenutil.NewVerificationTokenManager(accessSecret, time.Hour)
+	verificationToken, err := verification.CreateVerificationToken(&models.User{ID: userID, Email: "user@example.com"})
+	require.NoError(t, err)
+
+	_, err = tm.ParseMFAToken(verificationToken)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+
+	expired := tokenutil.NewMFATokenManager(accessSecret, -time.Minute)
+	tokenStr, err = expired.CreateMFAToken(userID)
+	require.NoError(t, err)
+
+	_, err = tm.ParseMFAToken(tokenStr)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+}
+
+func TestOIDCStateManager(t *testing.T) {
+	sm := tokenutil.NewOIDCStateManager(accessSecret, time.Minute)
+
+	state := models.OIDCState{
+		Provider: "google",
+		State:    "state",
+		Nonce:    "nonce",
+		Verifier: "verifier",
+	}
+
+	tokenStr, err := sm.CreateStateToken(state)
+	require.NoError(t, err)
+
+	got, err := sm.ParseStateToken(tokenStr)
+	require.NoError(t, err)
+	assert.Equal(t, state, got)
+
+	// Mfa tokens signed with the same secret are not state tokens.
+	mfaToken, err := tokenutil.NewMFATokenManager(accessSecret, time.Minute).CreateMFAToken(userID)
+	require.NoError(t, err)
+
+	_, err = sm.ParseStateToken(mfaToken)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+
+	expired := tokenutil.NewOIDCStateManager(accessSecret, -time.Minute)
+	tokenStr, err = expired.CreateStateToken(state)
+	require.NoError(t, err)
+
+	_, err = sm.ParseStateToken(tokenStr)
+	assert.ErrorIs(t, err, tokenutil.ErrInvalidToken)
+}
+
+func TestDeriveSecret(t *testing.T) {
+	secret := tokenutil.DeriveSecret(refreshSecret, "verification")
+
+	assert.Len(t, secret, 64)
+	assert.Equal(t, secret, tokenutil.DeriveSecret(refreshSecret, "verification"))
+	assert.NotEqual(t, secret, tokenutil.DeriveSecret(refreshSecret, "mfa"))
+	assert.NotEqual(t, secret, tokenutil.DeriveSecret(accessSecret, "verification"))
+}
@@ -0,0 +1,234 @@
+package tokenutil
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"slices"
+	"time"
+)
+
+const minRSAKeyBits = 2048
+
+var (
+	ErrNoSigningKey       = errors.New("no active signing key")
+	ErrUnsupportedKeyType = errors.New("unsupported key type, use RSA or Ed25519")
+)
+
+// SigningKey is an asymmetric key access tokens are signed with. The key signs tokens
+// from NotBefore until a newer key takes over, and tokens it signed are accepted
+// until RetireAt. A zero RetireAt keeps the key forever.
+type SigningKey struct {
+	ID         string
+	PrivateKey crypto.Signer
+	NotBefore  time.Time
+	RetireAt   time.Time
+}
+
+type keySetEntry struct {
+	SigningKey
+	method jwt.SigningMethod
+}
+
+// KeySet holds the signing keys of a rotation schedule. Keys are published in the
+// JWKS before they start signing, so that verifiers know them in time, and stay
+// published until they are retired.
+type KeySet struct {
+	keys []keySetEntry
+}
+
+// NewKeySet validates the keys. One of them must be able to sign tokens right now.
+func NewKeySet(keys ...SigningKey) (*KeySet, error) {
+	const op = "tokenutil.NewKeySet"
+
+	ks := &KeySet{keys: make([]keySetEntry, 0, len(keys))}
+
+	for _, k := range keys {
+		if k.ID == "" {
+			return nil, fmt.Errorf("%s: key id is required", op)
+		}
+
+		if slices.ContainsFunc(ks.keys, func(e keySetEntry) bool { return e.ID == k.ID }) {
+			return nil, fmt.Errorf("%s: duplicate key id %q", op, k.ID)
+		}
+
+		method, err := signingMethod(k.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: %w", op, k.ID, err)
+		}
+
+		ks.keys = append(ks.keys, keySetEntry{SigningKey: k, method: method})
+	}
+
+	// The newest key that already started signing is the current one.
+	slices.SortStableFunc(ks.keys, func(a, b keySetEntry) int {
+		return b.NotBefore.Compare(a.NotBefore)
+	})
+
+	if _, err := ks.signingKey(time.Now()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ks, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8 RSA or Ed25519 key, or a PKCS #1 RSA key.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	const op = "tokenutil.ParsePrivateKey"
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block found", op)
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unexpected PEM block %q", op, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, ErrUnsupportedKeyType)
+	}
+
+	if _, err := signingMethod(signer); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return signer, nil
+}
+
+// JWKS returns the public keys that aren't retired as a JSON Web Key Set, RFC 7517.
+func (ks *KeySet) JWKS() JWKS {
+	now := time.Now()
+	set := JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+
+	for _, k := range ks.keys {
+		if k.retired(now) {
+			continue
+		}
+
+		jwk := JWK{
+			KeyID:     k.ID,
+			Use:       "sig",
+			Algorithm: k.method.Alg(),
+		}
+
+		switch pub := k.PrivateKey.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func (ks *KeySet) signingKey(now time.Time) (keySetEntry, error) {
+	for _, k := range ks.keys {
+		if !k.NotBefore.After(now) && !k.retired(now) {
+			return k, nil
+		}
+	}
+
+	return keySetEntry{}, ErrNoSigningKey
+}
+
+// sign signs the claims with the current key and sets its id in the header.
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	k, err := ks.signingKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.ID
+
+	return token.SignedString(k.PrivateKey)
+}
+
+// keyFunc finds the public key of the token by its key id. Tokens must be signed
+// with the algorithm of the key, so a public key can't be used as an HMAC secret.
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	now := time.Now()
+	for _, k := range ks.keys {
+		if k.ID != kid {
+			continue
+		}
+
+		if k.retired(now) {
+			return nil, fmt.Errorf("key %q is retired", kid)
+		}
+
+		if token.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return k.PrivateKey.Public(), nil
+	}
+
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (k keySetEntry) retired(now time.Time) bool {
+	return !k.RetireAt.IsZero() && !now.Before(k.RetireAt)
+}
+
+func signingMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+		}
+
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is the public part of a signing key.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
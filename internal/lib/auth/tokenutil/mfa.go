@@ -0,0 +1,71 @@
+package tokenutil
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+)
+
+const mfaAudience = "mfa"
+
+// MFATokenManager issues the short-lived tokens proving that the user passed
+// the first login step and has to present the second factor.
+type MFATokenManager struct {
+	Secret string
+	Expiry time.Duration
+}
+
+func NewMFATokenManager(secret string, expiry time.Duration) *MFATokenManager {
+	return &MFATokenManager{
+		Secret: secret,
+		Expiry: expiry,
+	}
+}
+
+// CreateMFAToken creates an mfa pending token of the user.
+func (tm *MFATokenManager) CreateMFAToken(userID int64) (string, error) {
+	const op = "tokenutil.CreateMFAToken"
+
+	now := time.Now()
+	claims := &jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   strconv.FormatInt(userID, 10),
+		Audience:  jwt.ClaimStrings{mfaAudience},
+		ExpiresAt: jwt.NewNumericDate(now.Add(tm.Expiry)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(tm.Secret))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// ParseMFAToken validates the mfa pending token and returns the id of the user.
+func (tm *MFATokenManager) ParseMFAToken(tokenStr string) (int64, error) {
+	const op = "tokenutil.ParseMFAToken"
+
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(
+		tokenStr,
+		claims,
+		func(token *jwt.Token) (interface{}, error) { return []byte(tm.Secret), nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(mfaAudience),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	return id, nil
+}
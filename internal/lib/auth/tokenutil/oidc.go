@@ -0,0 +1,83 @@
+package tokenutil
+
+import (
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+const oidcStateAudience = "oidc_state"
+
+type oidcStateClaims struct {
+	jwt.RegisteredClaims
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// OIDCStateManager signs the state of external logins, so it can be kept
+// by the user agent between the redirect to the provider and the callback.
+type OIDCStateManager struct {
+	Secret string
+	Expiry time.Duration
+}
+
+func NewOIDCStateManager(secret string, expiry time.Duration) *OIDCStateManager {
+	return &OIDCStateManager{
+		Secret: secret,
+		Expiry: expiry,
+	}
+}
+
+// CreateStateToken creates a token holding the state of an external login.
+func (sm *OIDCStateManager) CreateStateToken(state models.OIDCState) (string, error) {
+	const op = "tokenutil.CreateStateToken"
+
+	now := time.Now()
+	claims := &oidcStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcStateAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(sm.Expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		Provider: state.Provider,
+		State:    state.State,
+		Nonce:    state.Nonce,
+		Verifier: state.Verifier,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(sm.Secret))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// ParseStateToken validates the token and returns the state of the external login.
+func (sm *OIDCStateManager) ParseStateToken(tokenStr string) (models.OIDCState, error) {
+	const op = "tokenutil.ParseStateToken"
+
+	claims := &oidcStateClaims{}
+	_, err := jwt.ParseWithClaims(
+		tokenStr,
+		claims,
+		func(token *jwt.Token) (interface{}, error) { return []byte(sm.Secret), nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(oidcStateAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return models.OIDCState{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	return models.OIDCState{
+		Provider: claims.Provider,
+		State:    claims.State,
+		Nonce:    claims.Nonce,
+		Verifier: claims.Verifier,
+	}, nil
+}
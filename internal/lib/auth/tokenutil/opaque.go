@@ -0,0 +1,33 @@
+package tokenutil
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const opaqueTokenBytes = 32
+
+// NewOpaqueToken returns a random url-safe token and its hash.
+// Only the hash is meant to be stored, so a leaked database doesn't leak usable tokens.
+func NewOpaqueToken() (string, string, error) {
+	const op = "tokenutil.NewOpaqueToken"
+
+	b := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken returns the hash of a token created by NewOpaqueToken.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+package tokenutil
+
+import (
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+)
+
+const verificationAudience = "email_verification"
+
+// VerificationClaims are the JWT claims of email verification tokens.
+// Email binds the token to the address it was sent to.
+type VerificationClaims struct {
+	jwt.RegisteredClaims
+	Email string `json:"email"`
+}
+
+// VerificationTokenManager issues signed email verification tokens.
+type VerificationTokenManager struct {
+	Secret string
+	Expiry time.Duration
+}
+
+func NewVerificationTokenManager(secret string, expiry time.Duration) *VerificationTokenManager {
+	return &VerificationTokenManager{
+		Secret: secret,
+		Expiry: expiry,
+	}
+}
+
+// CreateVerificationToken creates a token confirming that the user owns user.Email.
+func (tm *VerificationTokenManager) CreateVerificationToken(user *models.User) (string, error) {
+	const op = "tokenutil.CreateVerificationToken"
+
+	now := time.Now()
+	claims := &VerificationClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   strconv.FormatInt(user.ID, 10),
+			Audience:  jwt.ClaimStrings{verificationAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(tm.Expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		Email: user.Email,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(tm.Secret))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// ParseVerificationToken validates the token and returns the id of the user and the verified email.
+func (tm *VerificationTokenManager) ParseVerificationToken(tokenStr string) (int64, string, error) {
+	const op = "tokenutil.ParseVerificationToken"
+
+	claims := &VerificationClaims{}
+	_, err := jwt.ParseWithClaims(
+		tokenStr,
+		claims,
+		func(token *jwt.Token) (interface{}, error) { return []byte(tm.Secret), nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(verificationAudience),
+	)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || claims.Email == "" {
+		return 0, "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	return id, claims.Email, nil
+}
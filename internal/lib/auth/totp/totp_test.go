@@ -0,0 +1,79 @@
+package totp_test
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the base32 form of the SHA1 key of the RFC 6238 test vectors, "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238(t *testing.T) {
+	// The RFC lists 8 digit codes, 6 digit codes are their last 6 digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := totp.Code(rfcSecret, totp.Step(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, got, "time %d", tt.unix)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	step := totp.Step(now)
+
+	prev, err := totp.Code(secret, step-1)
+	require.NoError(t, err)
+
+	got, ok, err := totp.Match(secret, prev, now, 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, step-1, got)
+
+	old, err := totp.Code(secret, step-2)
+	require.NoError(t, err)
+
+	_, ok, err = totp.Match(secret, old, now, 1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = totp.Match(secret, "12345", now, 1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = totp.Match("not base32!", "123456", now, 1)
+	assert.ErrorIs(t, err, totp.ErrInvalidSecret)
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("Application Tracker", "jane@example.com", rfcSecret)
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Application Tracker:jane@example.com", u.Path)
+	assert.Equal(t, rfcSecret, u.Query().Get("secret"))
+	assert.Equal(t, "Application Tracker", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+	assert.Equal(t, "30", u.Query().Get("period"))
+}
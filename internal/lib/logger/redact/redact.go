@@ -0,0 +1,99 @@
+package redact
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// Placeholder replaces the values of sensitive attributes.
+const Placeholder = "[REDACTED]"
+
+// DefaultKeys are the attribute keys redacted when no others are given.
+var DefaultKeys = []string{"password", "token", "secret", "authorization", "cookie", "api_key"}
+
+// Handler is a slog.Handler that replaces the values of sensitive attributes before
+// passing the records on. An attribute is sensitive if its key is one of the keys or
+// ends with one of them, so "new_password" and "refresh-token" are redacted too.
+// Keys are compared ignoring case, attributes in groups are checked as well.
+type Handler struct {
+	next slog.Handler
+	keys []string
+}
+
+// NewHandler wraps next. Without keys DefaultKeys are used.
+func NewHandler(next slog.Handler, keys ...string) *Handler {
+	if len(keys) == 0 {
+		keys = DefaultKeys
+	}
+
+	normalized := make([]string, 0, len(keys))
+	for _, k := range keys {
+		normalized = append(normalized, normalizeKey(k))
+	}
+
+	return &Handler{next: next, keys: normalized}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redact(a))
+		return true
+	})
+
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		redacted = append(redacted, h.redact(a))
+	}
+
+	return &Handler{next: h.next.WithAttrs(redacted), keys: h.keys}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), keys: h.keys}
+}
+
+func (h *Handler) redact(a slog.Attr) slog.Attr {
+	if h.sensitive(a.Key) {
+		return slog.String(a.Key, Placeholder)
+	}
+
+	// LogValuers may expand into groups holding sensitive attributes.
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+
+	group := a.Value.Group()
+	redacted := make([]slog.Attr, 0, len(group))
+	for _, ga := range group {
+		redacted = append(redacted, h.redact(ga))
+	}
+
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
+
+func (h *Handler) sensitive(key string) bool {
+	key = normalizeKey(key)
+	for _, k := range h.keys {
+		if key == k || strings.HasSuffix(key, "_"+k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeKey ignores case and treats dashes as underscores, so "X-Api-Key" matches "api_key".
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
@@ -0,0 +1,80 @@
+package redact_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+type credentials struct {
+	Email    string
+	Password string
+}
+
+func (c credentials) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("email", c.Email), slog.String("password", c.Password))
+}
+
+func newLogger(buf *bytes.Buffer, keys ...string) *slog.Logger {
+	return slog.New(redact.NewHandler(slog.NewJSONHandler(buf, nil), keys...))
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	return entry
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := newLogger(&buf).With(slog.String("refresh_token", "rt"), slog.String("op", "test"))
+
+	log.WithGroup("req").Info(
+		"login",
+		slog.String("Password", "hunter2"),
+		slog.String("new-password", "hunter3"),
+		slog.String("email", "a@b.co"),
+		slog.Group("headers", slog.String("Authorization", "Bearer x"), slog.String("Accept", "*/*")),
+		slog.Any("credentials", credentials{Email: "a@b.co", Password: "hunter4"}),
+		slog.Int("token_count", 2),
+	)
+
+	entry := decode(t, &buf)
+	assert.Equal(t, redact.Placeholder, entry["refresh_token"])
+	assert.Equal(t, "test", entry["op"])
+
+	req := entry["req"].(map[string]any)
+	assert.Equal(t, redact.Placeholder, req["Password"])
+	assert.Equal(t, redact.Placeholder, req["new-password"])
+	assert.Equal(t, "a@b.co", req["email"])
+	assert.Equal(t, map[string]any{"Authorization": redact.Placeholder, "Accept": "*/*"}, req["headers"])
+	assert.Equal(t, map[string]any{"email": "a@b.co", "password": redact.Placeholder}, req["credentials"])
+	// Only keys ending with a sensitive one are redacted.
+	assert.Equal(t, float64(2), req["token_count"])
+}
+
+func TestHandler_Keys(t *testing.T) {
+	var buf bytes.Buffer
+	log := newLogger(&buf, "ssn")
+
+	log.Info("user", slog.String("user_ssn", "123"), slog.String("password", "hunter2"))
+
+	entry := decode(t, &buf)
+	assert.Equal(t, redact.Placeholder, entry["user_ssn"])
+	assert.Equal(t, "hunter2", entry["password"])
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(redact.NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	log.Info("hidden", slog.String("password", "hunter2"))
+	assert.Zero(t, buf.Len())
+}
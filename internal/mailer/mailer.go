@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Message is an email ready to be delivered.
+// HTML is optional, messages without it are sent as plain text.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer doesn't deliver messages, it only logs their recipients and subjects.
+// The bodies are left out as they contain one-time tokens; use FileMailer to read them.
+type LogMailer struct {
+	log *slog.Logger
+}
+
+func NewLogMailer(log *slog.Logger) *LogMailer {
+	return &LogMailer{log: log}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.log.Info(
+		"email message",
+		slog.String("to", msg.To),
+		slog.String("subject", msg.Subject),
+	)
+
+	return nil
+}
+
+// TemplateMailer renders messages from Templates and sends them with a Mailer.
+type TemplateMailer struct {
+	mailer    Mailer
+	templates *Templates
+}
+
+func NewTemplateMailer(mailer Mailer, templates *Templates) *TemplateMailer {
+	return &TemplateMailer{
+		mailer:    mailer,
+		templates: templates,
+	}
+}
+
+// SendTemplate renders the named email in the locale of the recipient and sends it to the address.
+func (m *TemplateMailer) SendTemplate(ctx context.Context, to, locale, name string, data any) error {
+	const op = "mailer.TemplateMailer.SendTemplate"
+
+	msg, err := m.templates.Render(name, locale, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	msg.To = to
+
+	if err := m.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package mailer_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+	var buf bytes.Buffer
+	m := mailer.NewLogMailer(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "jane@example.com",
+		Subject: "Reset your password",
+		Text:    "https://example.com/reset?token=secret-token",
+		HTML:    `<a href="https://example.com/reset?token=secret-token">Reset</a>`,
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "jane@example.com")
+	assert.Contains(t, buf.String(), "Reset your password")
+	assert.NotContains(t, buf.String(), "secret-token")
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := mailer.NewFileMailer(dir, "no-reply@example.com")
+
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "jane@example.com",
+		Subject: "Hello",
+		Text:    "Hello, Jane\nBye",
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "<no-reply@example.com>", msg.Header.Get("From"))
+	assert.Equal(t, "<jane@example.com>", msg.Header.Get("To"))
+	assert.Equal(t, "Hello", msg.Header.Get("Subject"))
+	assert.Contains(t, msg.Header.Get("Message-ID"), "@example.com>")
+
+	text, html := parts(t, msg)
+	assert.Equal(t, "Hello, Jane\r\nBye", text)
+	assert.Empty(t, html)
+}
+
+func TestFileMailer_Send_InvalidAddress(t *testing.T) {
+	m := mailer.NewFileMailer(t.TempDir(), "no-reply@example.com")
+
+	err := m.Send(context.Background(), mailer.Message{To: "not an address", Subject: "Hello", Text: "Hello"})
+	assert.Error(t, err)
+}
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := mailer.NewTemplates()
+	require.NoError(t, err)
+
+	data := mailer.TokenData{
+		Name:  "<Jane>",
+		Token: "secret",
+		Link:  "https://tracker.example.com/verify?token=secret",
+	}
+
+	tests := []struct {
+		name    string
+		locale  string
+		subject string
+	}{
+		{name: "default locale", locale: "", subject: "Confirm your email"},
+		{name: "exact locale", locale: "ru", subject: "Подтвердите адрес электронной почты"},
+		{name: "region falls back to language", locale: "ru_RU", subject: "Подтвердите адрес электронной почты"},
+		{name: "unknown locale falls back to default", locale: "xx-YY", subject: "Confirm your email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := templates.Render(mailer.TemplateVerification, tt.locale, data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.subject, msg.Subject)
+			assert.Contains(t, msg.Text, data.Link)
+			assert.Contains(t, msg.Text, "<Jane>")
+			assert.Contains(t, msg.HTML, `href="https://tracker.example.com/verify?token=secret"`)
+			assert.Contains(t, msg.HTML, "&lt;Jane&gt;")
+		})
+	}
+}
+
+func TestTemplates_Render_WithoutLink(t *testing.T) {
+	templates, err := mailer.NewTemplates()
+	require.NoError(t, err)
+
+	msg, err := templates.Render(mailer.TemplatePasswordReset, "en", mailer.TokenData{Token: "secret"})
+	require.NoError(t, err)
+
+	assert.Contains(t, msg.Text, "Your password reset token: secret")
+	assert.NotContains(t, msg.Text, "Hi ,")
+
+	_, err = templates.Render("unknown", "en", nil)
+	assert.Error(t, err)
+}
+
+// parts returns the decoded text and HTML bodies of msg.
+func parts(t *testing.T, msg *mail.Message) (string, string) {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+
+	if mediaType == "text/plain" {
+		return decodeQuotedPrintable(t, msg.Body), ""
+	}
+
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	var text, html string
+
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		// multipart.Reader decodes quoted-printable parts itself.
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		switch {
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain"):
+			text = string(body)
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/html"):
+			html = string(body)
+		}
+	}
+
+	return text, html
+}
+
+func decodeQuotedPrintable(t *testing.T, r io.Reader) string {
+	t.Helper()
+
+	body, err := io.ReadAll(quotedprintable.NewReader(r))
+	require.NoError(t, err)
+
+	return string(body)
+}
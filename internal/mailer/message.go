@@ -0,0 +1,127 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// encode renders msg as an RFC 5322 message with CRLF line endings.
+// Messages with an HTML body become multipart/alternative with the text part first,
+// so clients that can't show HTML fall back to it.
+func encode(from, to *mail.Address, msg Message, date time.Time) ([]byte, error) {
+	id, err := messageID(from)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", id)
+	header("MIME-Version", "1.0")
+
+	if msg.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+
+	for _, part := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()}))
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qw := quotedprintable.NewWriter(w)
+
+	if _, err := qw.Write([]byte(s)); err != nil {
+		return err
+	}
+
+	return qw.Close()
+}
+
+// messageID returns a random Message-ID in the domain of the sender.
+func messageID(from *mail.Address) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from.Address, "@"); ok && d != "" {
+		domain = d
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain), nil
+}
+
+// addresses parses the sender and the recipient of a message.
+func addresses(from, to string) (*mail.Address, *mail.Address, error) {
+	fromAddr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	toAddr, err := mail.ParseAddress(to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	return fromAddr, toAddr, nil
+}
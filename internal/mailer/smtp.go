@@ -0,0 +1,125 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender address, e.g. "Application Tracker <no-reply@example.com>".
+	From string
+	// Timeout bounds the whole SMTP exchange of a message.
+	Timeout time.Duration
+}
+
+// SMTPMailer delivers messages to an SMTP server. The connection is upgraded
+// with STARTTLS when the server supports it, and authenticated with PLAIN auth
+// when a username is set.
+type SMTPMailer struct {
+	opts SMTPOptions
+}
+
+func NewSMTPMailer(opts SMTPOptions) *SMTPMailer {
+	return &SMTPMailer{opts: opts}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	const op = "mailer.SMTPMailer.Send"
+
+	from, to, err := addresses(m.opts.From, msg.To)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	data, err := encode(from, to, msg, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if m.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.opts.Timeout)
+		defer cancel()
+	}
+
+	if err := m.send(ctx, from.Address, to.Address, data); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (m *SMTPMailer) send(ctx context.Context, from, to string, data []byte) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.opts.Host, strconv.Itoa(m.opts.Port)))
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+
+			return err
+		}
+	}
+
+	// Unblock the exchange as soon as ctx is cancelled.
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	c, err := smtp.NewClient(conn, m.opts.Host)
+	if err != nil {
+		conn.Close()
+
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.opts.Host}); err != nil {
+			return err
+		}
+	}
+
+	if m.opts.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.opts.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
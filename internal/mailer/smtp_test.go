@@ -0,0 +1,178 @@
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpServer is a fake SMTP server accepting every message.
+// It speaks just enough of the protocol for net/smtp.
+type smtpServer struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	auth     []string
+	from     []string
+	to       []string
+	messages []string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &smtpServer{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		cmd, arg, _ := strings.Cut(line, " ")
+
+		s.mu.Lock()
+
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = append(s.auth, arg)
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.from = append(s.from, arg)
+			reply("250 OK")
+		case "RCPT":
+			s.to = append(s.to, arg)
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					s.mu.Unlock()
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+
+			s.messages = append(s.messages, data.String())
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("250 OK")
+		}
+
+		s.mu.Unlock()
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	srv := newSMTPServer(t)
+
+	m := mailer.NewSMTPMailer(mailer.SMTPOptions{
+		Host:     "127.0.0.1",
+		Port:     srv.port(),
+		Username: "user",
+		Password: "secret",
+		From:     "Application Tracker <no-reply@example.com>",
+		Timeout:  5 * time.Second,
+	})
+
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "Jane <jane@example.com>",
+		Subject: "Привет",
+		Text:    "Hello, Jane",
+		HTML:    "<p>Hello, Jane</p>",
+	})
+	require.NoError(t, err)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	require.Len(t, srv.messages, 1)
+	assert.Len(t, srv.auth, 1)
+	assert.Equal(t, []string{"FROM:<no-reply@example.com>"}, srv.from)
+	assert.Equal(t, []string{"TO:<jane@example.com>"}, srv.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(srv.messages[0]))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Привет", subject)
+	assert.Equal(t, `"Jane" <jane@example.com>`, msg.Header.Get("To"))
+
+	text, html := parts(t, msg)
+	assert.Equal(t, "Hello, Jane", text)
+	assert.Equal(t, "<p>Hello, Jane</p>", html)
+}
+
+func TestSMTPMailer_Send_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	m := mailer.NewSMTPMailer(mailer.SMTPOptions{
+		Host:    "127.0.0.1",
+		Port:    port,
+		From:    "no-reply@example.com",
+		Timeout: time.Second,
+	})
+
+	err = m.Send(context.Background(), mailer.Message{To: "jane@example.com", Subject: "Hi", Text: "Hi"})
+	assert.Error(t, err)
+}
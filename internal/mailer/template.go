@@ -0,0 +1,184 @@
+package mailer
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DefaultLocale is used for recipients whose locale has no templates.
+const DefaultLocale = "en"
+
+// Names of the emails in templates.
+const (
+	TemplateVerification  = "verification"
+	TemplatePasswordReset = "password_reset"
+)
+
+// TokenData is the data of the emails carrying a one-time token.
+// Link is empty when no page to open the token with is configured.
+type TokenData struct {
+	Name  string
+	Token string
+	Link  string
+}
+
+//go:embed templates
+var templatesFS embed.FS
+
+// Templates renders emails from the templates embedded in the package.
+// An email is a directory per locale, e.g. templates/en, holding <name>.subject.tmpl,
+// <name>.txt.tmpl and optionally <name>.html.tmpl.
+type Templates struct {
+	// locales maps a locale to its emails by name.
+	locales map[string]map[string]*emailTemplate
+}
+
+type emailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// NewTemplates parses the embedded templates.
+func NewTemplates() (*Templates, error) {
+	const op = "mailer.NewTemplates"
+
+	sub, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	t, err := parseTemplates(sub)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, ok := t.locales[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("%s: no templates for default locale %q", op, DefaultLocale)
+	}
+
+	return t, nil
+}
+
+func parseTemplates(fsys fs.FS) (*Templates, error) {
+	t := &Templates{locales: make(map[string]map[string]*emailTemplate)}
+
+	dirs, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		locale := dir.Name()
+
+		subjects, err := fs.Glob(fsys, path.Join(locale, "*.subject.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+
+		emails := make(map[string]*emailTemplate, len(subjects))
+
+		for _, subject := range subjects {
+			name := strings.TrimSuffix(path.Base(subject), ".subject.tmpl")
+
+			email, err := parseEmail(fsys, locale, name)
+			if err != nil {
+				return nil, fmt.Errorf("email %s/%s: %w", locale, name, err)
+			}
+
+			emails[name] = email
+		}
+
+		t.locales[strings.ToLower(locale)] = emails
+	}
+
+	return t, nil
+}
+
+func parseEmail(fsys fs.FS, locale, name string) (*emailTemplate, error) {
+	base := path.Join(locale, name)
+
+	var (
+		email emailTemplate
+		err   error
+	)
+
+	email.subject, err = texttemplate.ParseFS(fsys, base+".subject.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	email.text, err = texttemplate.ParseFS(fsys, base+".txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fs.Stat(fsys, base+".html.tmpl"); err == nil {
+		email.html, err = htmltemplate.ParseFS(fsys, base+".html.tmpl")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &email, nil
+}
+
+// Render renders the named email in the given locale. Locales without the email
+// fall back to their base language ("pt-BR" to "pt") and then to DefaultLocale.
+// The recipient of the returned message is empty.
+func (t *Templates) Render(name, locale string, data any) (Message, error) {
+	const op = "mailer.Templates.Render"
+
+	email, ok := t.lookup(name, locale)
+	if !ok {
+		return Message{}, fmt.Errorf("%s: unknown email %q", op, name)
+	}
+
+	var (
+		subject strings.Builder
+		text    strings.Builder
+		html    strings.Builder
+	)
+
+	if err := email.subject.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := email.text.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if email.html != nil {
+		if err := email.html.Execute(&html, data); err != nil {
+			return Message{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func (t *Templates) lookup(name, locale string) (*emailTemplate, bool) {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	base, _, _ := strings.Cut(locale, "-")
+
+	for _, l := range []string{locale, base, DefaultLocale} {
+		if email, ok := t.locales[l][name]; ok {
+			return email, true
+		}
+	}
+
+	return nil, false
+}
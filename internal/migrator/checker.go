@@ -0,0 +1,37 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/pressly/goose/v3"
+	"io/fs"
+)
+
+// Checker reports whether the database schema lags behind the migrations.
+type Checker struct {
+	provider *goose.Provider
+}
+
+func NewChecker(db *sql.DB, fsys fs.FS, driver string) (*Checker, error) {
+	const op = "migrator.NewChecker"
+
+	provider, err := newProvider(db, fsys, driver)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Checker{provider: provider}, nil
+}
+
+// HasPending returns true if there are migrations that haven't been applied yet.
+func (c *Checker) HasPending(ctx context.Context) (bool, error) {
+	const op = "migrator.HasPending"
+
+	pending, err := c.provider.HasPending(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pending, nil
+}
@@ -1,41 +1,399 @@
 package migrator
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/diproducts/application-tracker-go/internal/config"
 	"github.com/pressly/goose/v3"
-	"log"
+	"github.com/pressly/goose/v3/lock"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
 
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
-func Up(cfg *config.Database, migrationsPath string) {
+// ErrPendingMigrations is returned by Status if the database lags behind the migrations.
+var ErrPendingMigrations = errors.New("there are pending migrations")
+
+// Migrator applies and inspects migrations found in fsys.
+// Results are reported to out. In dry-run mode the SQL that would be
+// executed is printed instead of being applied.
+type Migrator struct {
+	db       *sql.DB
+	fsys     fs.FS
+	provider *goose.Provider
+	out      io.Writer
+	dryRun   bool
+}
+
+func New(cfg *config.Database, fsys fs.FS, out io.Writer, dryRun bool) (*Migrator, error) {
+	const op = "migrator.New"
+
 	db, err := initDB(cfg)
 	if err != nil {
-		log.Println("[ERROR] failed to connect to database:", err.Error())
-		return
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	provider, err := newProvider(db, fsys, cfg.Driver)
+	if err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Migrator{
+		db:       db,
+		fsys:     fsys,
+		provider: provider,
+		out:      out,
+		dryRun:   dryRun,
+	}, nil
+}
+
+// Close closes the database connection.
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.UpTo(ctx, goose.MaxVersion)
+}
+
+// UpTo applies pending migrations up to and including the given version.
+func (m *Migrator) UpTo(ctx context.Context, version int64) error {
+	const op = "migrator.UpTo"
+
+	if m.dryRun {
+		pending, err := m.migrations(ctx, goose.StatePending)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		pending = slices.DeleteFunc(pending, func(s *goose.Source) bool { return s.Version > version })
+
+		return m.printPlan(pending, true)
+	}
+
+	results, err := m.provider.UpTo(ctx, version)
+	m.printResults(results...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(m.out, "no migrations to apply")
+	}
+
+	return nil
+}
+
+// Down rolls back the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	const op = "migrator.Down"
+
+	if m.dryRun {
+		applied, err := m.migrations(ctx, goose.StateApplied)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(applied) > 0 {
+			applied = applied[len(applied)-1:]
+		}
+
+		return m.printPlan(applied, false)
+	}
+
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			fmt.Fprintln(m.out, "no migrations to roll back")
+
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.printResults(result)
+
+	return nil
+}
+
+// DownTo rolls back applied migrations down to, but not including, the given version.
+func (m *Migrator) DownTo(ctx context.Context, version int64) error {
+	const op = "migrator.DownTo"
+
+	if m.dryRun {
+		applied, err := m.migrations(ctx, goose.StateApplied)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		applied = slices.DeleteFunc(applied, func(s *goose.Source) bool { return s.Version <= version })
+		slices.Reverse(applied)
+
+		return m.printPlan(applied, false)
+	}
+
+	results, err := m.provider.DownTo(ctx, version)
+	m.printResults(results...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) error {
+	const op = "migrator.Redo"
+
+	applied, err := m.migrations(ctx, goose.StateApplied)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(applied) == 0 {
+		fmt.Fprintln(m.out, "no migrations to redo")
+
+		return nil
+	}
+
+	last := applied[len(applied)-1]
+
+	if m.dryRun {
+		if err := m.printPlan([]*goose.Source{last}, false); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return m.printPlan([]*goose.Source{last}, true)
+	}
+
+	for _, direction := range []bool{false, true} {
+		result, err := m.provider.ApplyVersion(ctx, last.Version, direction)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		m.printResults(result)
+	}
+
+	return nil
+}
+
+// Reset rolls back all applied migrations.
+func (m *Migrator) Reset(ctx context.Context) error {
+	return m.DownTo(ctx, 0)
+}
+
+// Version prints the current version of the database.
+func (m *Migrator) Version(ctx context.Context) error {
+	const op = "migrator.Version"
+
+	version, err := m.provider.GetDBVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	defer db.Close()
 
-	err = goose.Up(db, migrationsPath)
+	fmt.Fprintf(m.out, "version: %d\n", version)
+
+	return nil
+}
+
+// Status prints the state of every migration.
+// Returns ErrPendingMigrations if some of them are not applied.
+func (m *Migrator) Status(ctx context.Context) error {
+	const op = "migrator.Status"
+
+	statuses, err := m.provider.Status(ctx)
 	if err != nil {
-		if errors.Is(err, goose.ErrAlreadyApplied) {
-			log.Println("no migrations to apply")
-			return
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	w := tabwriter.NewWriter(m.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Applied At\tMigration")
+
+	hasPending := false
+	for _, s := range statuses {
+		appliedAt := "Pending"
+		if s.State == goose.StateApplied {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		} else {
+			hasPending = true
 		}
 
-		log.Println("[ERROR] failed to apply migrations:", err.Error())
-		return
+		fmt.Fprintf(w, "%s\t%s\n", appliedAt, filepath.Base(s.Source.Path))
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if hasPending {
+		return fmt.Errorf("%s: %w", op, ErrPendingMigrations)
+	}
+
+	return nil
+}
+
+// Apply applies all pending migrations in fsys to db. On postgres it holds
+// an advisory lock while doing so, so several replicas starting at once
+// don't race each other. Returns the number of applied migrations.
+func Apply(ctx context.Context, db *sql.DB, fsys fs.FS, driver string) (int, error) {
+	const op = "migrator.Apply"
+
+	provider, err := newProvider(db, fsys, driver)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	results, err := provider.Up(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	log.Println("migrations applied successfully")
+	return len(results), nil
+}
+
+// Create writes a new empty sql migration with the next sequential version into dir.
+func Create(dir, name string) error {
+	const op = "migrator.Create"
+
+	goose.SetSequential(true)
+
+	if err := goose.Create(nil, dir, name, "sql"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// migrations returns sources of migrations in the given state ordered by version.
+func (m *Migrator) migrations(ctx context.Context, state goose.State) ([]*goose.Source, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []*goose.Source
+	for _, s := range statuses {
+		if s.State == state {
+			sources = append(sources, s.Source)
+		}
+	}
+
+	return sources, nil
+}
+
+// printPlan prints the SQL of the migrations in the given direction without running it.
+func (m *Migrator) printPlan(sources []*goose.Source, up bool) error {
+	if len(sources) == 0 {
+		fmt.Fprintln(m.out, "-- nothing to do")
+
+		return nil
+	}
+
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	for _, s := range sources {
+		content, err := fs.ReadFile(m.fsys, s.Path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(m.out, "-- %s %s\n", direction, filepath.Base(s.Path))
+		fmt.Fprintln(m.out, migrationSQL(content, up))
+	}
+
+	return nil
+}
+
+func (m *Migrator) printResults(results ...*goose.MigrationResult) {
+	for _, r := range results {
+		fmt.Fprintln(m.out, r.String())
+	}
+}
+
+// migrationSQL returns the up or down section of a goose sql migration
+// with goose annotations stripped.
+func migrationSQL(content []byte, up bool) string {
+	want := "-- +goose Down"
+	if up {
+		want = "-- +goose Up"
+	}
+
+	var (
+		b       strings.Builder
+		inWant  bool
+		scanner = bufio.NewScanner(bytes.NewReader(content))
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "-- +goose ") {
+			switch trimmed {
+			case "-- +goose Up", "-- +goose Down":
+				inWant = trimmed == want
+			}
+
+			continue
+		}
+
+		if inWant {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// newProvider creates a goose provider for the given database driver.
+// Postgres migrations are serialized with an advisory lock.
+func newProvider(db *sql.DB, fsys fs.FS, driver string) (*goose.Provider, error) {
+	switch driver {
+	case config.DriverPostgres:
+		locker, err := lock.NewPostgresSessionLocker()
+		if err != nil {
+			return nil, err
+		}
+
+		return goose.NewProvider(goose.DialectPostgres, db, fsys, goose.WithSessionLocker(locker))
+	case config.DriverSQLite:
+		return goose.NewProvider(goose.DialectSQLite3, db, fsys)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
 }
 
 func initDB(dbCfg *config.Database) (*sql.DB, error) {
 	const op = "migrator.initDB"
 
+	if dbCfg.Driver == config.DriverSQLite {
+		db, err := sql.Open("sqlite", dbCfg.SQLiteDSN())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return db, nil
+	}
+
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		dbCfg.Host,
 		dbCfg.Port,
@@ -0,0 +1,23 @@
+package migrator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMigrationSQL(t *testing.T) {
+	content := []byte(`-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE t (id INT);
+CREATE INDEX idx ON t (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE t;
+-- +goose StatementEnd
+`)
+
+	assert.Equal(t, "CREATE TABLE t (id INT);\nCREATE INDEX idx ON t (id);", migrationSQL(content, true))
+	assert.Equal(t, "DROP TABLE t;", migrationSQL(content, false))
+}
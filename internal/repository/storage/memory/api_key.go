@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"slices"
+	"time"
+)
+
+type APIKeyRepository struct {
+	db *DB
+}
+
+func NewAPIKeyRepository(db *DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// SaveAPIKey stores a new key with the hash of its secret and returns its id.
+// Returns storage.ErrAPIKeyAlreadyExists if the user has a key with the same name.
+func (kr *APIKeyRepository) SaveAPIKey(ctx context.Context, key *models.APIKey, keyHash string) (int64, error) {
+	const op = "storage.memory.SaveAPIKey"
+
+	kr.db.mu.Lock()
+	defer kr.db.mu.Unlock()
+
+	for _, k := range kr.db.apiKeys {
+		if k.hash == keyHash || (k.UserID == key.UserID && k.Name == key.Name) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyAlreadyExists)
+		}
+	}
+
+	kr.db.lastAPIKeyID++
+
+	saved := *key
+	saved.ID = kr.db.lastAPIKeyID
+	saved.Scopes = slices.Clone(key.Scopes)
+	saved.Created = key.Created.UTC()
+	saved.LastUsedAt = nil
+
+	kr.db.apiKeys[saved.ID] = apiKey{APIKey: saved, hash: keyHash}
+
+	return saved.ID, nil
+}
+
+// APIKeys returns the keys of the user, newest first.
+func (kr *APIKeyRepository) APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	kr.db.mu.RLock()
+	defer kr.db.mu.RUnlock()
+
+	keys := make([]models.APIKey, 0)
+	for _, k := range kr.db.apiKeys {
+		if k.UserID == userID {
+			keys = append(keys, k.APIKey)
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b models.APIKey) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	return keys, nil
+}
+
+// UseAPIKey sets the last use of the key with the given hash to at and returns the key.
+// Returns storage.ErrAPIKeyNotFound if there is no such key.
+func (kr *APIKeyRepository) UseAPIKey(ctx context.Context, keyHash string, at time.Time) (models.APIKey, error) {
+	const op = "storage.memory.UseAPIKey"
+
+	kr.db.mu.Lock()
+	defer kr.db.mu.Unlock()
+
+	for id, k := range kr.db.apiKeys {
+		if k.hash != keyHash {
+			continue
+		}
+
+		at = at.UTC()
+		k.LastUsedAt = &at
+		kr.db.apiKeys[id] = k
+
+		return k.APIKey, nil
+	}
+
+	return models.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+}
+
+// DeleteAPIKey removes the key with the given id owned by userID.
+// Returns storage.ErrAPIKeyNotFound if there is no such key.
+func (kr *APIKeyRepository) DeleteAPIKey(ctx context.Context, id, userID int64) error {
+	const op = "storage.memory.DeleteAPIKey"
+
+	kr.db.mu.Lock()
+	defer kr.db.mu.Unlock()
+
+	k, ok := kr.db.apiKeys[id]
+	if !ok || k.UserID != userID {
+		return fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	delete(kr.db.apiKeys, id)
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/query"
+	"slices"
+	"strings"
+	"time"
+)
+
+type ApplicationRepository struct {
+	db *DB
+}
+
+func NewApplicationRepository(db *DB) *ApplicationRepository {
+	return &ApplicationRepository{db: db}
+}
+
+// SaveApplication stores a new application and returns its id.
+func (ar *ApplicationRepository) SaveApplication(ctx context.Context, app *models.Application) (int64, error) {
+	const op = "storage.memory.SaveApplication"
+
+	ar.db.mu.Lock()
+	defer ar.db.mu.Unlock()
+
+	if _, ok := ar.db.users[app.OwnerID]; !ok {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	ar.db.lastApplicationID++
+
+	now := time.Now().UTC()
+	saved := *app
+	saved.ID = ar.db.lastApplicationID
+	saved.Created = now
+	saved.LastModified = now
+	ar.db.applications[saved.ID] = saved
+
+	return saved.ID, nil
+}
+
+// Application returns the application with the given id owned by ownerID.
+func (ar *ApplicationRepository) Application(ctx context.Context, id, ownerID int64) (models.Application, error) {
+	const op = "storage.memory.Application"
+
+	ar.db.mu.RLock()
+	defer ar.db.mu.RUnlock()
+
+	app, ok := ar.db.applications[id]
+	if !ok || app.OwnerID != ownerID {
+		return models.Application{}, fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+	}
+
+	return app, nil
+}
+
+// Applications returns a page of applications owned by ownerID
+// matching q.Filter and ordered by q.Sort.
+func (ar *ApplicationRepository) Applications(
+	ctx context.Context,
+	ownerID int64,
+	q models.ApplicationQuery,
+) (models.ApplicationPage, error) {
+	const op = "storage.memory.Applications"
+
+	anchor, err := query.ApplicationAnchor(q)
+	if err != nil {
+		return models.ApplicationPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ar.db.mu.RLock()
+	defer ar.db.mu.RUnlock()
+
+	apps := make([]models.Application, 0)
+	for _, app := range ar.db.applications {
+		if app.OwnerID != ownerID || !ar.matches(&app, &q.Filter) {
+			continue
+		}
+
+		if anchor != nil && query.CompareApplications(&app, anchor, q.Sort) <= 0 {
+			continue
+		}
+
+		apps = append(apps, app)
+	}
+
+	slices.SortFunc(apps, func(a, b models.Application) int {
+		return query.CompareApplications(&a, &b, q.Sort)
+	})
+
+	if len(apps) > q.Limit+1 {
+		apps = apps[:q.Limit+1]
+	}
+
+	return query.ApplicationPage(apps, q), nil
+}
+
+// SearchApplications returns at most limit applications owned by ownerID containing
+// every term of q, best matches first.
+func (ar *ApplicationRepository) SearchApplications(
+	ctx context.Context,
+	ownerID int64,
+	q string,
+	limit int,
+) ([]models.ApplicationSearchHit, error) {
+	terms := query.SearchTerms(q)
+	if len(terms) == 0 {
+		return make([]models.ApplicationSearchHit, 0), nil
+	}
+
+	ar.db.mu.RLock()
+	defer ar.db.mu.RUnlock()
+
+	apps := make([]models.Application, 0)
+	for _, app := range ar.db.applications {
+		if app.OwnerID == ownerID {
+			apps = append(apps, app)
+		}
+	}
+
+	return query.SearchHits(apps, terms, limit), nil
+}
+
+// UpdateApplication overwrites the editable fields of the application
+// identified by app.ID and app.OwnerID and bumps LastModified.
+func (ar *ApplicationRepository) UpdateApplication(ctx context.Context, app *models.Application) error {
+	const op = "storage.memory.UpdateApplication"
+
+	ar.db.mu.Lock()
+	defer ar.db.mu.Unlock()
+
+	stored, ok := ar.db.applications[app.ID]
+	if !ok || stored.OwnerID != app.OwnerID {
+		return fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+	}
+
+	stored.CompanyName = app.CompanyName
+	stored.Position = app.Position
+	stored.Url = app.Url
+	stored.JobDescription = app.JobDescription
+	stored.Contacts = app.Contacts
+	stored.Cv = app.Cv
+	stored.CoverLetter = app.CoverLetter
+	stored.OfferedSalary = app.OfferedSalary
+	stored.LastModified = time.Now().UTC()
+	ar.db.applications[app.ID] = stored
+
+	return nil
+}
+
+// DeleteApplication removes the application with the given id owned by ownerID
+// together with its phases.
+func (ar *ApplicationRepository) DeleteApplication(ctx context.Context, id, ownerID int64) error {
+	const op = "storage.memory.DeleteApplication"
+
+	ar.db.mu.Lock()
+	defer ar.db.mu.Unlock()
+
+	app, ok := ar.db.applications[id]
+	if !ok || app.OwnerID != ownerID {
+		return fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+	}
+
+	delete(ar.db.applications, id)
+
+	for phaseID, phase := range ar.db.phases {
+		if phase.ApplicationID == id {
+			delete(ar.db.phases, phaseID)
+		}
+	}
+
+	return nil
+}
+
+// matches reports whether app passes the filter. Must be called with the lock held.
+func (ar *ApplicationRepository) matches(app *models.Application, f *models.ApplicationFilter) bool {
+	switch {
+	case f.CompanyName != "" && !containsFold(app.CompanyName, f.CompanyName),
+		f.Position != "" && !containsFold(app.Position, f.Position),
+		f.MinSalary != nil && app.OfferedSalary < *f.MinSalary,
+		f.MaxSalary != nil && app.OfferedSalary > *f.MaxSalary,
+		f.CreatedFrom != nil && app.Created.Before(*f.CreatedFrom),
+		f.CreatedTo != nil && !app.Created.Before(*f.CreatedTo),
+		f.ModifiedFrom != nil && app.LastModified.Before(*f.ModifiedFrom),
+		f.ModifiedTo != nil && !app.LastModified.Before(*f.ModifiedTo),
+		f.Phase != "" && ar.currentPhase(app.ID) != f.Phase:
+		return false
+	}
+
+	return true
+}
+
+// currentPhase returns the name of the latest phase of the application timeline.
+// Must be called with the lock held.
+func (ar *ApplicationRepository) currentPhase(applicationID int64) string {
+	var current *models.ApplicationPhase
+
+	for _, p := range ar.db.phases {
+		if p.ApplicationID != applicationID {
+			continue
+		}
+
+		if current == nil || comparePhases(&p, current) > 0 {
+			current = &p
+		}
+	}
+
+	if current == nil {
+		return ""
+	}
+
+	return current.Name
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
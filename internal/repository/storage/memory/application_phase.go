@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"slices"
+	"time"
+)
+
+type ApplicationPhaseRepository struct {
+	db *DB
+}
+
+func NewApplicationPhaseRepository(db *DB) *ApplicationPhaseRepository {
+	return &ApplicationPhaseRepository{db: db}
+}
+
+// SavePhase appends a new phase to the application phase.ApplicationID
+// if the application belongs to ownerID. Returns an id of the created phase.
+func (pr *ApplicationPhaseRepository) SavePhase(
+	ctx context.Context,
+	ownerID int64,
+	phase *models.ApplicationPhase,
+) (int64, error) {
+	const op = "storage.memory.SavePhase"
+
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	if !pr.ownsApplication(ownerID, phase.ApplicationID) {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+	}
+
+	position := 0
+	for _, p := range pr.db.phases {
+		if p.ApplicationID == phase.ApplicationID && p.Position >= position {
+			position = p.Position + 1
+		}
+	}
+
+	pr.db.lastPhaseID++
+
+	saved := *phase
+	saved.ID = pr.db.lastPhaseID
+	saved.Created = time.Now().UTC()
+	saved.Position = position
+	pr.db.phases[saved.ID] = saved
+
+	return saved.ID, nil
+}
+
+// Phases returns the timeline of the application ordered by date.
+// Phases with the same date keep their manual order.
+func (pr *ApplicationPhaseRepository) Phases(
+	ctx context.Context,
+	ownerID, applicationID int64,
+) ([]models.ApplicationPhase, error) {
+	const op = "storage.memory.Phases"
+
+	pr.db.mu.RLock()
+	defer pr.db.mu.RUnlock()
+
+	if !pr.ownsApplication(ownerID, applicationID) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+	}
+
+	phases := make([]models.ApplicationPhase, 0)
+	for _, p := range pr.db.phases {
+		if p.ApplicationID == applicationID {
+			phases = append(phases, p)
+		}
+	}
+
+	slices.SortFunc(phases, func(a, b models.ApplicationPhase) int {
+		return comparePhases(&a, &b)
+	})
+
+	return phases, nil
+}
+
+// UpdatePhase overwrites name, date and notes of the phase identified by
+// phase.ID and phase.ApplicationID if the application belongs to ownerID.
+func (pr *ApplicationPhaseRepository) UpdatePhase(
+	ctx context.Context,
+	ownerID int64,
+	phase *models.ApplicationPhase,
+) error {
+	const op = "storage.memory.UpdatePhase"
+
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	stored, ok := pr.db.phases[phase.ID]
+	if !ok || stored.ApplicationID != phase.ApplicationID || !pr.ownsApplication(ownerID, stored.ApplicationID) {
+		return fmt.Errorf("%s: %w", op, storage.ErrPhaseNotFound)
+	}
+
+	stored.Name = phase.Name
+	stored.Date = phase.Date
+	stored.Notes = phase.Notes
+	pr.db.phases[phase.ID] = stored
+
+	return nil
+}
+
+// DeletePhase removes the phase from the application if the application belongs to ownerID.
+func (pr *ApplicationPhaseRepository) DeletePhase(ctx context.Context, ownerID, applicationID, id int64) error {
+	const op = "storage.memory.DeletePhase"
+
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	stored, ok := pr.db.phases[id]
+	if !ok || stored.ApplicationID != applicationID || !pr.ownsApplication(ownerID, applicationID) {
+		return fmt.Errorf("%s: %w", op, storage.ErrPhaseNotFound)
+	}
+
+	delete(pr.db.phases, id)
+
+	return nil
+}
+
+// ReorderPhases sets the position of every phase in ids to its index in the slice.
+// Either all phases are reordered or none.
+func (pr *ApplicationPhaseRepository) ReorderPhases(
+	ctx context.Context,
+	ownerID, applicationID int64,
+	ids []int64,
+) error {
+	const op = "storage.memory.ReorderPhases"
+
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	if !pr.ownsApplication(ownerID, applicationID) {
+		return fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+	}
+
+	for _, id := range ids {
+		if p, ok := pr.db.phases[id]; !ok || p.ApplicationID != applicationID {
+			return fmt.Errorf("%s: %w", op, storage.ErrPhaseNotFound)
+		}
+	}
+
+	for position, id := range ids {
+		p := pr.db.phases[id]
+		p.Position = position
+		pr.db.phases[id] = p
+	}
+
+	return nil
+}
+
+// ownsApplication must be called with the lock held.
+func (pr *ApplicationPhaseRepository) ownsApplication(ownerID, applicationID int64) bool {
+	app, ok := pr.db.applications[applicationID]
+	return ok && app.OwnerID == ownerID
+}
+
+// comparePhases orders phases of a timeline by date, manual position and id.
+func comparePhases(a, b *models.ApplicationPhase) int {
+	if c := a.Date.Compare(b.Date); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(a.Position, b.Position); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.ID, b.ID)
+}
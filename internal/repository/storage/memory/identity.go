@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+)
+
+type IdentityRepository struct {
+	db *DB
+}
+
+func NewIdentityRepository(db *DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// SaveIdentity links the external identity to its user.
+// Returns storage.ErrIdentityAlreadyExists if the identity is linked already.
+func (ir *IdentityRepository) SaveIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	const op = "storage.memory.SaveIdentity"
+
+	ir.db.mu.Lock()
+	defer ir.db.mu.Unlock()
+
+	if _, ok := ir.db.users[identity.UserID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	key := identityKey{provider: identity.Provider, subject: identity.Subject}
+	if _, ok := ir.db.identities[key]; ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrIdentityAlreadyExists)
+	}
+
+	ir.db.identities[key] = *identity
+
+	return nil
+}
+
+// Identity returns the identity with the subject at the provider.
+func (ir *IdentityRepository) Identity(ctx context.Context, provider, subject string) (models.UserIdentity, error) {
+	const op = "storage.memory.Identity"
+
+	ir.db.mu.RLock()
+	defer ir.db.mu.RUnlock()
+
+	identity, ok := ir.db.identities[identityKey{provider: provider, subject: subject}]
+	if !ok {
+		return models.UserIdentity{}, fmt.Errorf("%s: %w", op, storage.ErrIdentityNotFound)
+	}
+
+	return identity, nil
+}
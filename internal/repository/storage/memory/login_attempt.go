@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"time"
+)
+
+// LoginAttemptRepository counts failed logins by key, such as an account or a client address.
+type LoginAttemptRepository struct {
+	db *DB
+}
+
+func NewLoginAttemptRepository(db *DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// LoginAttempts returns the failed logins of the key. Keys without failures have none.
+func (lr *LoginAttemptRepository) LoginAttempts(ctx context.Context, key string) (models.LoginAttempts, error) {
+	lr.db.mu.RLock()
+	defer lr.db.mu.RUnlock()
+
+	return lr.db.loginAttempts[key].LoginAttempts, nil
+}
+
+// ReserveLoginAttempt counts an attempt to log in with the key before it is checked, unless
+// the key is locked out at the given time. Earlier attempts are forgotten if the last one and
+// the end of the lockout are both before since. Reserving the attempt that reaches maxAttempts
+// locks out the key until lockUntil, so that no other attempt is checked in the meantime.
+// Reports whether the attempt was reserved.
+func (lr *LoginAttemptRepository) ReserveLoginAttempt(
+	ctx context.Context,
+	key string,
+	at time.Time,
+	since time.Time,
+	maxAttempts int,
+	lockUntil time.Time,
+) (models.LoginAttempts, bool, error) {
+	lr.db.mu.Lock()
+	defer lr.db.mu.Unlock()
+
+	attempt, ok := lr.db.loginAttempts[key]
+	if !ok || attempt.expired(since) {
+		attempt = loginAttempt{}
+	}
+
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(at) {
+		return attempt.LoginAttempts, false, nil
+	}
+
+	attempt.Failures++
+	attempt.LockedUntil = nil
+	if attempt.Failures >= maxAttempts {
+		attempt.LockedUntil = &lockUntil
+	}
+	attempt.lastFailedAt = at
+	lr.db.loginAttempts[key] = attempt
+
+	return attempt.LoginAttempts, true, nil
+}
+
+// ReleaseLoginAttempt takes back an attempt reserved with ReserveLoginAttempt that turned out
+// not to fail. The lockout taken by reaching maxAttempts is lifted with it.
+func (lr *LoginAttemptRepository) ReleaseLoginAttempt(ctx context.Context, key string, maxAttempts int) error {
+	lr.db.mu.Lock()
+	defer lr.db.mu.Unlock()
+
+	attempt, ok := lr.db.loginAttempts[key]
+	if !ok || attempt.Failures == 0 {
+		return nil
+	}
+
+	if attempt.Failures == maxAttempts {
+		attempt.LockedUntil = nil
+	}
+	attempt.Failures--
+	lr.db.loginAttempts[key] = attempt
+
+	return nil
+}
+
+// LockLogin locks out logins of the key until the given time.
+func (lr *LoginAttemptRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	lr.db.mu.Lock()
+	defer lr.db.mu.Unlock()
+
+	attempt, ok := lr.db.loginAttempts[key]
+	if !ok {
+		return nil
+	}
+
+	attempt.LockedUntil = &until
+	lr.db.loginAttempts[key] = attempt
+
+	return nil
+}
+
+// ResetLoginAttempts forgets the failed logins of the key and lifts its lockout.
+func (lr *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	lr.db.mu.Lock()
+	defer lr.db.mu.Unlock()
+
+	delete(lr.db.loginAttempts, key)
+
+	return nil
+}
+
+// DeleteExpiredLoginAttempts removes keys whose last failure and lockout both ended before the given time.
+// Returns the number of removed keys.
+func (lr *LoginAttemptRepository) DeleteExpiredLoginAttempts(ctx context.Context, before time.Time) (int64, error) {
+	lr.db.mu.Lock()
+	defer lr.db.mu.Unlock()
+
+	var n int64
+	for key, attempt := range lr.db.loginAttempts {
+		if attempt.expired(before) {
+			delete(lr.db.loginAttempts, key)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (a loginAttempt) expired(before time.Time) bool {
+	return a.lastFailedAt.Before(before) && (a.LockedUntil == nil || a.LockedUntil.Before(before))
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"sync"
+	"time"
+)
+
+// DB is a thread-safe in-memory database shared by the memory repositories.
+// It mirrors the constraints of the sql schema, including cascade deletes.
+type DB struct {
+	mu sync.RWMutex
+
+	lastUserID        int64
+	lastApplicationID int64
+	lastPhaseID       int64
+	lastAPIKeyID      int64
+
+	users        map[int64]models.User
+	applications map[int64]models.Application
+	phases       map[int64]models.ApplicationPhase
+	families     map[string]tokenFamily
+	blacklist    map[string]blacklistedToken
+	resetTokens  map[string]resetToken
+	totp         map[int64]models.TOTP
+	// recoveryCodes holds the set of recovery code hashes of every user.
+	recoveryCodes map[int64]map[string]struct{}
+	apiKeys       map[int64]apiKey
+	identities    map[identityKey]models.UserIdentity
+	loginAttempts map[string]loginAttempt
+	buckets       map[string]models.TokenBucket
+}
+
+type tokenFamily struct {
+	models.Session
+	revoked bool
+}
+
+type blacklistedToken struct {
+	userID int64
+	expiry time.Time
+}
+
+type resetToken struct {
+	userID int64
+	expiry time.Time
+}
+
+type apiKey struct {
+	models.APIKey
+	hash string
+}
+
+type loginAttempt struct {
+	models.LoginAttempts
+	lastFailedAt time.Time
+}
+
+type identityKey struct {
+	provider string
+	subject  string
+}
+
+func NewDB() *DB {
+	return &DB{
+		users:         make(map[int64]models.User),
+		applications:  make(map[int64]models.Application),
+		phases:        make(map[int64]models.ApplicationPhase),
+		families:      make(map[string]tokenFamily),
+		blacklist:     make(map[string]blacklistedToken),
+		resetTokens:   make(map[string]resetToken),
+		totp:          make(map[int64]models.TOTP),
+		recoveryCodes: make(map[int64]map[string]struct{}),
+		apiKeys:       make(map[int64]apiKey),
+		identities:    make(map[identityKey]models.UserIdentity),
+		loginAttempts: make(map[string]loginAttempt),
+		buckets:       make(map[string]models.TokenBucket),
+	}
+}
+
+// PingContext always succeeds, it is there to satisfy readiness checks.
+func (db *DB) PingContext(ctx context.Context) error {
+	return nil
+}
+
+// HasPending always returns false: the in-memory database has no schema to migrate.
+func (db *DB) HasPending(ctx context.Context) (bool, error) {
+	return false, nil
+}
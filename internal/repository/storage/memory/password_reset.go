@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"time"
+)
+
+type PasswordResetRepository struct {
+	db *DB
+}
+
+func NewPasswordResetRepository(db *DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// SaveResetToken stores the hash of a password reset token of the user.
+func (pr *PasswordResetRepository) SaveResetToken(
+	ctx context.Context,
+	userID int64,
+	tokenHash string,
+	expiry time.Time,
+) error {
+	const op = "storage.memory.SaveResetToken"
+
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	if _, ok := pr.db.users[userID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	pr.db.resetTokens[tokenHash] = resetToken{userID: userID, expiry: expiry}
+
+	return nil
+}
+
+// ConsumeResetToken deletes the token if it hasn't expired by now and returns the id of its user.
+// Returns storage.ErrResetTokenNotFound if there is no such token.
+func (pr *PasswordResetRepository) ConsumeResetToken(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	const op = "storage.memory.ConsumeResetToken"
+
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	token, ok := pr.db.resetTokens[tokenHash]
+	if !ok || !token.expiry.After(now) {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrResetTokenNotFound)
+	}
+
+	delete(pr.db.resetTokens, tokenHash)
+
+	return token.userID, nil
+}
+
+// DeleteUserResetTokens removes every outstanding reset token of the user.
+func (pr *PasswordResetRepository) DeleteUserResetTokens(ctx context.Context, userID int64) error {
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	for hash, token := range pr.db.resetTokens {
+		if token.userID == userID {
+			delete(pr.db.resetTokens, hash)
+		}
+	}
+
+	return nil
+}
+
+// DeleteExpiredResetTokens removes tokens that expired before the given time.
+// Returns the number of removed tokens.
+func (pr *PasswordResetRepository) DeleteExpiredResetTokens(ctx context.Context, before time.Time) (int64, error) {
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	var n int64
+	for hash, token := range pr.db.resetTokens {
+		if token.expiry.Before(before) {
+			delete(pr.db.resetTokens, hash)
+			n++
+		}
+	}
+
+	return n, nil
+}
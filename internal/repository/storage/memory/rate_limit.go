@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"time"
+)
+
+// RateLimitRepository keeps the token buckets of rate limited keys.
+type RateLimitRepository struct {
+	db *DB
+}
+
+func NewRateLimitRepository(db *DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// TakeToken takes a token from the bucket of the key if it has one. Keys without a bucket start with a full one.
+func (rr *RateLimitRepository) TakeToken(
+	ctx context.Context,
+	key string,
+	limit models.RateLimit,
+	now time.Time,
+) (models.RateLimitResult, error) {
+	rr.db.mu.Lock()
+	defer rr.db.mu.Unlock()
+
+	bucket, ok := rr.db.buckets[key]
+	if !ok {
+		bucket = models.NewTokenBucket(limit, now)
+	}
+
+	bucket, res := bucket.Take(limit, now)
+	rr.db.buckets[key] = bucket
+
+	return res, nil
+}
+
+// DeleteIdleBuckets removes buckets that weren't used since the given time.
+// Returns the number of removed buckets.
+func (rr *RateLimitRepository) DeleteIdleBuckets(ctx context.Context, before time.Time) (int64, error) {
+	rr.db.mu.Lock()
+	defer rr.db.mu.Unlock()
+
+	var n int64
+	for key, bucket := range rr.db.buckets {
+		if bucket.Updated.Before(before) {
+			delete(rr.db.buckets, key)
+			n++
+		}
+	}
+
+	return n, nil
+}
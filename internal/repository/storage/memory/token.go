@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"slices"
+	"strings"
+	"time"
+)
+
+type TokenRepository struct {
+	db *DB
+}
+
+func NewTokenRepository(db *DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+// BlacklistToken stores user id, token id and expiry time in the blacklist.
+func (tr *TokenRepository) BlacklistToken(ctx context.Context, userID int64, tokenID string, expiry time.Time) error {
+	const op = "storage.memory.BlacklistToken"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	if _, ok := tr.db.blacklist[tokenID]; ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrTokenAlreadyBlacklisted)
+	}
+
+	tr.db.blacklist[tokenID] = blacklistedToken{userID: userID, expiry: expiry}
+
+	return nil
+}
+
+// IsBlacklisted checks if the token is blacklisted.
+func (tr *TokenRepository) IsBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	tr.db.mu.RLock()
+	defer tr.db.mu.RUnlock()
+
+	_, ok := tr.db.blacklist[tokenID]
+
+	return ok, nil
+}
+
+// SaveFamily stores a new token family for the user, the session of a login.
+func (tr *TokenRepository) SaveFamily(ctx context.Context, session models.Session) error {
+	const op = "storage.memory.SaveFamily"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	if _, ok := tr.db.users[session.UserID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	tr.db.families[session.ID] = tokenFamily{Session: session}
+
+	return nil
+}
+
+// UseFamily records that a refresh token of the family was issued to the client at the given time.
+func (tr *TokenRepository) UseFamily(ctx context.Context, familyID string, client models.Client, at time.Time) error {
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	if family, ok := tr.db.families[familyID]; ok {
+		family.UserAgent = client.UserAgent
+		family.IP = client.IP
+		family.LastUsedAt = at
+		tr.db.families[familyID] = family
+	}
+
+	return nil
+}
+
+// IsFamilyRevoked checks if the token family was revoked.
+// Unknown families are reported as revoked.
+func (tr *TokenRepository) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	tr.db.mu.RLock()
+	defer tr.db.mu.RUnlock()
+
+	family, ok := tr.db.families[familyID]
+
+	return !ok || family.revoked, nil
+}
+
+// RevokeFamily marks the token family as revoked so none of its tokens can be used anymore.
+func (tr *TokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	if family, ok := tr.db.families[familyID]; ok {
+		family.revoked = true
+		tr.db.families[familyID] = family
+	}
+
+	return nil
+}
+
+// RevokeUserFamilies revokes every token family of the user.
+func (tr *TokenRepository) RevokeUserFamilies(ctx context.Context, userID int64) error {
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	for id, family := range tr.db.families {
+		if family.UserID == userID {
+			family.revoked = true
+			tr.db.families[id] = family
+		}
+	}
+
+	return nil
+}
+
+// DeleteExpiredTokens removes blacklist entries of tokens that expired before the given time.
+// Returns the number of removed entries.
+func (tr *TokenRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	var n int64
+	for id, token := range tr.db.blacklist {
+		if token.expiry.Before(before) {
+			delete(tr.db.blacklist, id)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// Sessions returns the token families of the user that aren't revoked and were
+// used at or after since, most recently used first.
+func (tr *TokenRepository) Sessions(ctx context.Context, userID int64, since time.Time) ([]models.Session, error) {
+	tr.db.mu.RLock()
+	defer tr.db.mu.RUnlock()
+
+	sessions := []models.Session{}
+	for _, family := range tr.db.families {
+		if family.UserID == userID && !family.revoked && !family.LastUsedAt.Before(since) {
+			sessions = append(sessions, family.Session)
+		}
+	}
+
+	slices.SortFunc(sessions, func(a, b models.Session) int {
+		if c := b.LastUsedAt.Compare(a.LastUsedAt); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession revokes the token family with the given id owned by userID.
+// Returns storage.ErrSessionNotFound if there is no such family or it is already revoked.
+func (tr *TokenRepository) RevokeSession(ctx context.Context, userID int64, id string) error {
+	const op = "storage.memory.RevokeSession"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	family, ok := tr.db.families[id]
+	if !ok || family.UserID != userID || family.revoked {
+		return fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	}
+
+	family.revoked = true
+	tr.db.families[id] = family
+
+	return nil
+}
+
+// DeleteExpiredFamilies removes token families last used before the given time.
+// Returns the number of removed families.
+func (tr *TokenRepository) DeleteExpiredFamilies(ctx context.Context, before time.Time) (int64, error) {
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	var n int64
+	for id, family := range tr.db.families {
+		if family.LastUsedAt.Before(before) {
+			delete(tr.db.families, id)
+			n++
+		}
+	}
+
+	return n, nil
+}
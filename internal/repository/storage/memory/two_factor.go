@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	db *DB
+}
+
+func NewTwoFactorRepository(db *DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SaveTOTP stores a pending totp secret of the user, replacing the previous pending one.
+// Returns storage.ErrTOTPAlreadyEnabled if the user has a confirmed one.
+func (tr *TwoFactorRepository) SaveTOTP(ctx context.Context, userID int64, secret string) error {
+	const op = "storage.memory.SaveTOTP"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	if totp, ok := tr.db.totp[userID]; ok && totp.ConfirmedAt != nil {
+		return fmt.Errorf("%s: %w", op, storage.ErrTOTPAlreadyEnabled)
+	}
+
+	tr.db.totp[userID] = models.TOTP{
+		UserID: userID,
+		Secret: secret,
+	}
+
+	return nil
+}
+
+// TOTP returns the totp enrollment of the user, pending or confirmed.
+func (tr *TwoFactorRepository) TOTP(ctx context.Context, userID int64) (models.TOTP, error) {
+	const op = "storage.memory.TOTP"
+
+	tr.db.mu.RLock()
+	defer tr.db.mu.RUnlock()
+
+	totp, ok := tr.db.totp[userID]
+	if !ok {
+		return models.TOTP{}, fmt.Errorf("%s: %w", op, storage.ErrTOTPNotFound)
+	}
+
+	return totp, nil
+}
+
+// ConfirmTOTP enables the pending totp of the user and replaces the recovery codes with the given hashes.
+// Returns storage.ErrTOTPNotFound if there is no pending totp.
+func (tr *TwoFactorRepository) ConfirmTOTP(ctx context.Context, userID int64, at time.Time, codeHashes []string) error {
+	const op = "storage.memory.ConfirmTOTP"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	totp, ok := tr.db.totp[userID]
+	if !ok || totp.ConfirmedAt != nil {
+		return fmt.Errorf("%s: %w", op, storage.ErrTOTPNotFound)
+	}
+
+	at = at.UTC()
+	totp.ConfirmedAt = &at
+	tr.db.totp[userID] = totp
+
+	codes := make(map[string]struct{}, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes[hash] = struct{}{}
+	}
+	tr.db.recoveryCodes[userID] = codes
+
+	return nil
+}
+
+// UseTOTPStep records that a code of the time step was accepted.
+// Returns storage.ErrTOTPStepUsed if a code of this or a later step was accepted before.
+func (tr *TwoFactorRepository) UseTOTPStep(ctx context.Context, userID, step int64) error {
+	const op = "storage.memory.UseTOTPStep"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	totp, ok := tr.db.totp[userID]
+	if !ok || totp.LastStep >= step {
+		return fmt.Errorf("%s: %w", op, storage.ErrTOTPStepUsed)
+	}
+
+	totp.LastStep = step
+	tr.db.totp[userID] = totp
+
+	return nil
+}
+
+// UseRecoveryCode deletes the recovery code of the user.
+// Returns storage.ErrRecoveryCodeNotFound if there is no such code.
+func (tr *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	const op = "storage.memory.UseRecoveryCode"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	if _, ok := tr.db.recoveryCodes[userID][codeHash]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrRecoveryCodeNotFound)
+	}
+
+	delete(tr.db.recoveryCodes[userID], codeHash)
+
+	return nil
+}
+
+// DeleteTOTP removes the totp and the recovery codes of the user.
+// Returns storage.ErrTOTPNotFound if the user has no totp.
+func (tr *TwoFactorRepository) DeleteTOTP(ctx context.Context, userID int64) error {
+	const op = "storage.memory.DeleteTOTP"
+
+	tr.db.mu.Lock()
+	defer tr.db.mu.Unlock()
+
+	if _, ok := tr.db.totp[userID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrTOTPNotFound)
+	}
+
+	delete(tr.db.totp, userID)
+	delete(tr.db.recoveryCodes, userID)
+
+	return nil
+}
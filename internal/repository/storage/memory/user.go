@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"time"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (ur *UserRepository) SaveUser(ctx context.Context, user *models.User) (int64, error) {
+	const op = "storage.memory.SaveUser"
+
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	for _, u := range ur.db.users {
+		if u.Email == user.Email {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserAlreadyExists)
+		}
+	}
+
+	ur.db.lastUserID++
+
+	saved := *user
+	saved.ID = ur.db.lastUserID
+	ur.db.users[saved.ID] = saved
+
+	return saved.ID, nil
+}
+
+func (ur *UserRepository) User(ctx context.Context, email string) (models.User, error) {
+	const op = "storage.memory.User"
+
+	ur.db.mu.RLock()
+	defer ur.db.mu.RUnlock()
+
+	for _, u := range ur.db.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+
+	return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+}
+
+func (ur *UserRepository) UserByID(ctx context.Context, id int64) (models.User, error) {
+	const op = "storage.memory.UserByID"
+
+	ur.db.mu.RLock()
+	defer ur.db.mu.RUnlock()
+
+	user, ok := ur.db.users[id]
+	if !ok {
+		return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return user, nil
+}
+
+// VerifyUser marks the email of the user as verified if it is still the given one
+// and wasn't verified yet. Returns storage.ErrUserNotFound otherwise.
+func (ur *UserRepository) VerifyUser(ctx context.Context, id int64, email string, at time.Time) error {
+	const op = "storage.memory.VerifyUser"
+
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	user, ok := ur.db.users[id]
+	if !ok || user.Email != email || user.VerifiedAt != nil {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	at = at.UTC()
+	user.VerifiedAt = &at
+	ur.db.users[id] = user
+
+	return nil
+}
+
+// UpdatePassword replaces the password hash of the user.
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id int64, hashedPassword string) error {
+	const op = "storage.memory.UpdatePassword"
+
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	user, ok := ur.db.users[id]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	user.HashedPassword = hashedPassword
+	ur.db.users[id] = user
+
+	return nil
+}
+
+// UpdateUser saves the email, name, locale and verification time of the user.
+func (ur *UserRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	const op = "storage.memory.UpdateUser"
+
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	saved, ok := ur.db.users[user.ID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	for _, u := range ur.db.users {
+		if u.ID != user.ID && u.Email == user.Email {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserAlreadyExists)
+		}
+	}
+
+	saved.Email = user.Email
+	saved.Name = user.Name
+	saved.Locale = user.Locale
+	saved.VerifiedAt = nil
+	if user.VerifiedAt != nil {
+		at := user.VerifiedAt.UTC()
+		saved.VerifiedAt = &at
+	}
+	ur.db.users[user.ID] = saved
+
+	return nil
+}
+
+// DeleteUser deletes the user together with everything the sql schema deletes by cascade:
+// applications with their phases, token families, blacklisted tokens, reset tokens and 2FA.
+func (ur *UserRepository) DeleteUser(ctx context.Context, id int64) error {
+	const op = "storage.memory.DeleteUser"
+
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	if _, ok := ur.db.users[id]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	delete(ur.db.users, id)
+
+	for appID, app := range ur.db.applications {
+		if app.OwnerID != id {
+			continue
+		}
+
+		delete(ur.db.applications, appID)
+
+		for phaseID, phase := range ur.db.phases {
+			if phase.ApplicationID == appID {
+				delete(ur.db.phases, phaseID)
+			}
+		}
+	}
+
+	for familyID, family := range ur.db.families {
+		if family.UserID == id {
+			delete(ur.db.families, familyID)
+		}
+	}
+
+	for tokenID, token := range ur.db.blacklist {
+		if token.userID == id {
+			delete(ur.db.blacklist, tokenID)
+		}
+	}
+
+	for hash, token := range ur.db.resetTokens {
+		if token.userID == id {
+			delete(ur.db.resetTokens, hash)
+		}
+	}
+
+	delete(ur.db.totp, id)
+	delete(ur.db.recoveryCodes, id)
+
+	for keyID, key := range ur.db.apiKeys {
+		if key.UserID == id {
+			delete(ur.db.apiKeys, keyID)
+		}
+	}
+
+	for key, identity := range ur.db.identities {
+		if identity.UserID == id {
+			delete(ur.db.identities, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"strings"
+	"time"
+)
+
+const apiKeyColumns = "id, user_id, name, prefix, scopes, last_used_at, created"
+
+// apiKeyRow is an api_keys row, scopes are stored space separated.
+type apiKeyRow struct {
+	ID         int64      `db:"id"`
+	UserID     int64      `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	Scopes     string     `db:"scopes"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	Created    time.Time  `db:"created"`
+}
+
+func (r apiKeyRow) model() models.APIKey {
+	return models.APIKey{
+		ID:         r.ID,
+		UserID:     r.UserID,
+		Name:       r.Name,
+		Prefix:     r.Prefix,
+		Scopes:     strings.Fields(r.Scopes),
+		LastUsedAt: r.LastUsedAt,
+		Created:    r.Created,
+	}
+}
+
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// SaveAPIKey stores a new key with the hash of its secret and returns its id.
+// Returns storage.ErrAPIKeyAlreadyExists if the user has a key with the same name.
+func (kr *APIKeyRepository) SaveAPIKey(ctx context.Context, key *models.APIKey, keyHash string) (int64, error) {
+	const op = "storage.postgresql.SaveAPIKey"
+
+	stmt, err := kr.db.PreparexContext(
+		ctx,
+		`INSERT INTO api_keys(user_id, name, prefix, key_hash, scopes, created)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err = stmt.QueryRowxContext(
+		ctx,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		keyHash,
+		strings.Join(key.Scopes, " "),
+		key.Created.UTC(),
+	).Scan(&id)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == uniqueViolationErrorCode {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyAlreadyExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// APIKeys returns the keys of the user, newest first.
+func (kr *APIKeyRepository) APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	const op = "storage.postgresql.APIKeys"
+
+	var rows []apiKeyRow
+	err := kr.db.SelectContext(
+		ctx,
+		&rows,
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE user_id = $1 ORDER BY id DESC;",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make([]models.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.model())
+	}
+
+	return keys, nil
+}
+
+// UseAPIKey sets the last use of the key with the given hash to at and returns the key.
+// Returns storage.ErrAPIKeyNotFound if there is no such key.
+func (kr *APIKeyRepository) UseAPIKey(ctx context.Context, keyHash string, at time.Time) (models.APIKey, error) {
+	const op = "storage.postgresql.UseAPIKey"
+
+	stmt, err := kr.db.PreparexContext(
+		ctx,
+		"UPDATE api_keys SET last_used_at = $1 WHERE key_hash = $2 RETURNING "+apiKeyColumns+";",
+	)
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var row apiKeyRow
+	err = stmt.GetContext(ctx, &row, at.UTC(), keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+		}
+
+		return models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return row.model(), nil
+}
+
+// DeleteAPIKey removes the key with the given id owned by userID.
+// Returns storage.ErrAPIKeyNotFound if there is no such key.
+func (kr *APIKeyRepository) DeleteAPIKey(ctx context.Context, id, userID int64) error {
+	const op = "storage.postgresql.DeleteAPIKey"
+
+	stmt, err := kr.db.PreparexContext(ctx, "DELETE FROM api_keys WHERE id = $1 AND user_id = $2;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrAPIKeyNotFound)
+}
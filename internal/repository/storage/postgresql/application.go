@@ -0,0 +1,209 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/query"
+	"github.com/jmoiron/sqlx"
+)
+
+type ApplicationRepository struct {
+	db *sqlx.DB
+}
+
+func NewApplicationRepository(db *sqlx.DB) *ApplicationRepository {
+	return &ApplicationRepository{db: db}
+}
+
+// SaveApplication stores a new application and returns its id.
+// Created and LastModified are set by the database.
+func (ar *ApplicationRepository) SaveApplication(ctx context.Context, app *models.Application) (int64, error) {
+	const op = "storage.postgresql.SaveApplication"
+
+	stmt, err := ar.db.PreparexContext(
+		ctx,
+		`INSERT INTO applications(
+			company_name, position, url, job_description, contacts, cv, cover_letter, offered_salary, owner_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err = stmt.QueryRowxContext(
+		ctx,
+		app.CompanyName,
+		app.Position,
+		app.Url,
+		app.JobDescription,
+		app.Contacts,
+		app.Cv,
+		app.CoverLetter,
+		app.OfferedSalary,
+		app.OwnerID,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Application returns the application with the given id owned by ownerID.
+func (ar *ApplicationRepository) Application(ctx context.Context, id, ownerID int64) (models.Application, error) {
+	const op = "storage.postgresql.Application"
+
+	stmt, err := ar.db.PreparexContext(
+		ctx,
+		"SELECT "+applicationColumns+" FROM applications WHERE id = $1 AND owner_id = $2;",
+	)
+	if err != nil {
+		return models.Application{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var app models.Application
+	err = stmt.GetContext(ctx, &app, id, ownerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Application{}, fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+		}
+
+		return models.Application{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// Applications returns a page of applications owned by ownerID
+// matching q.Filter and ordered by q.Sort.
+func (ar *ApplicationRepository) Applications(
+	ctx context.Context,
+	ownerID int64,
+	q models.ApplicationQuery,
+) (models.ApplicationPage, error) {
+	const op = "storage.postgresql.Applications"
+
+	sel, err := query.Applications(applicationColumns, query.Lower, ownerID, q)
+	if err != nil {
+		return models.ApplicationPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, args := sel.Build(sqlx.DOLLAR)
+
+	apps := make([]models.Application, 0, q.Limit+1)
+	err = ar.db.SelectContext(ctx, &apps, stmt, args...)
+	if err != nil {
+		return models.ApplicationPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return query.ApplicationPage(apps, q), nil
+}
+
+// SearchApplications returns at most limit applications owned by ownerID matching
+// the web search query q, best matches first.
+func (ar *ApplicationRepository) SearchApplications(
+	ctx context.Context,
+	ownerID int64,
+	q string,
+	limit int,
+) ([]models.ApplicationSearchHit, error) {
+	const op = "storage.postgresql.SearchApplications"
+
+	stmt, err := ar.db.PreparexContext(
+		ctx,
+		`SELECT `+applicationColumns+`,
+			ts_rank_cd(search, query) AS rank,
+			ts_headline(
+				'english',
+				-- Escape the text the same way html.EscapeString does, ts_headline adds the marks only.
+				replace(replace(replace(replace(replace(
+					concat_ws(' ', company_name, position, job_description, contacts, cover_letter),
+					'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+				query,
+				'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5'
+			) AS snippet
+		FROM applications, websearch_to_tsquery('english', $2) AS query
+		WHERE owner_id = $1 AND search @@ query
+		ORDER BY rank DESC, id DESC
+		LIMIT $3;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hits := make([]models.ApplicationSearchHit, 0)
+	err = stmt.SelectContext(ctx, &hits, ownerID, q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, nil
+}
+
+// UpdateApplication overwrites the editable fields of the application
+// identified by app.ID and app.OwnerID and bumps last_modified.
+func (ar *ApplicationRepository) UpdateApplication(ctx context.Context, app *models.Application) error {
+	const op = "storage.postgresql.UpdateApplication"
+
+	stmt, err := ar.db.PreparexContext(
+		ctx,
+		`UPDATE applications SET
+			company_name = $1,
+			position = $2,
+			url = $3,
+			job_description = $4,
+			contacts = $5,
+			cv = $6,
+			cover_letter = $7,
+			offered_salary = $8,
+			last_modified = now()
+		WHERE id = $9 AND owner_id = $10;`,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(
+		ctx,
+		app.CompanyName,
+		app.Position,
+		app.Url,
+		app.JobDescription,
+		app.Contacts,
+		app.Cv,
+		app.CoverLetter,
+		app.OfferedSalary,
+		app.ID,
+		app.OwnerID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrApplicationNotFound)
+}
+
+// DeleteApplication removes the application with the given id owned by ownerID.
+func (ar *ApplicationRepository) DeleteApplication(ctx context.Context, id, ownerID int64) error {
+	const op = "storage.postgresql.DeleteApplication"
+
+	stmt, err := ar.db.PreparexContext(ctx, "DELETE FROM applications WHERE id = $1 AND owner_id = $2;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id, ownerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrApplicationNotFound)
+}
+
+const applicationColumns = `id, company_name, position, url, job_description, contacts, cv, cover_letter,
+	offered_salary, created, last_modified, owner_id`
@@ -0,0 +1,202 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+type ApplicationPhaseRepository struct {
+	db *sqlx.DB
+}
+
+func NewApplicationPhaseRepository(db *sqlx.DB) *ApplicationPhaseRepository {
+	return &ApplicationPhaseRepository{db: db}
+}
+
+// SavePhase appends a new phase to the application phase.ApplicationID
+// if the application belongs to ownerID. Returns an id of the created phase.
+func (pr *ApplicationPhaseRepository) SavePhase(
+	ctx context.Context,
+	ownerID int64,
+	phase *models.ApplicationPhase,
+) (int64, error) {
+	const op = "storage.postgresql.SavePhase"
+
+	stmt, err := pr.db.PreparexContext(
+		ctx,
+		`INSERT INTO application_phases(name, date, notes, position, application_id)
+		SELECT $1, $2, $3,
+			COALESCE((SELECT MAX(position) + 1 FROM application_phases WHERE application_id = a.id), 0),
+			a.id
+		FROM applications a
+		WHERE a.id = $4 AND a.owner_id = $5
+		RETURNING id;`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err = stmt.QueryRowxContext(ctx, phase.Name, phase.Date, phase.Notes, phase.ApplicationID, ownerID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Phases returns the timeline of the application ordered by date.
+// Phases with the same date keep their manual order.
+func (pr *ApplicationPhaseRepository) Phases(
+	ctx context.Context,
+	ownerID, applicationID int64,
+) ([]models.ApplicationPhase, error) {
+	const op = "storage.postgresql.Phases"
+
+	if err := pr.checkApplication(ctx, ownerID, applicationID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := pr.db.PreparexContext(
+		ctx,
+		`SELECT id, name, date, created, notes, position, application_id
+		FROM application_phases
+		WHERE application_id = $1
+		ORDER BY date, position, id;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	phases := make([]models.ApplicationPhase, 0)
+	err = stmt.SelectContext(ctx, &phases, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return phases, nil
+}
+
+// UpdatePhase overwrites name, date and notes of the phase identified by
+// phase.ID and phase.ApplicationID if the application belongs to ownerID.
+func (pr *ApplicationPhaseRepository) UpdatePhase(
+	ctx context.Context,
+	ownerID int64,
+	phase *models.ApplicationPhase,
+) error {
+	const op = "storage.postgresql.UpdatePhase"
+
+	stmt, err := pr.db.PreparexContext(
+		ctx,
+		`UPDATE application_phases p SET name = $1, date = $2, notes = $3
+		FROM applications a
+		WHERE p.id = $4 AND p.application_id = $5 AND a.id = p.application_id AND a.owner_id = $6;`,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, phase.Name, phase.Date, phase.Notes, phase.ID, phase.ApplicationID, ownerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrPhaseNotFound)
+}
+
+// DeletePhase removes the phase from the application if the application belongs to ownerID.
+func (pr *ApplicationPhaseRepository) DeletePhase(ctx context.Context, ownerID, applicationID, id int64) error {
+	const op = "storage.postgresql.DeletePhase"
+
+	stmt, err := pr.db.PreparexContext(
+		ctx,
+		`DELETE FROM application_phases p
+		USING applications a
+		WHERE p.id = $1 AND p.application_id = $2 AND a.id = p.application_id AND a.owner_id = $3;`,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id, applicationID, ownerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrPhaseNotFound)
+}
+
+// ReorderPhases sets the position of every phase in ids to its index in the slice.
+// Either all phases are reordered or none.
+func (pr *ApplicationPhaseRepository) ReorderPhases(
+	ctx context.Context,
+	ownerID, applicationID int64,
+	ids []int64,
+) error {
+	const op = "storage.postgresql.ReorderPhases"
+
+	if err := pr.checkApplication(ctx, ownerID, applicationID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := pr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PreparexContext(
+		ctx,
+		"UPDATE application_phases SET position = $1 WHERE id = $2 AND application_id = $3;",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for position, id := range ids {
+		res, err := stmt.ExecContext(ctx, position, id, applicationID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := checkAffected(op, res, storage.ErrPhaseNotFound); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// checkApplication returns storage.ErrApplicationNotFound if there is no
+// application with the given id owned by ownerID.
+func (pr *ApplicationPhaseRepository) checkApplication(ctx context.Context, ownerID, applicationID int64) error {
+	var exists bool
+	err := pr.db.QueryRowxContext(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM applications WHERE id = $1 AND owner_id = $2);",
+		applicationID,
+		ownerID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrApplicationNotFound
+	}
+
+	return nil
+}
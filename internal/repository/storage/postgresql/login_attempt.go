@@ -0,0 +1,182 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// LoginAttemptRepository counts failed logins by key, such as an account or a client address.
+// It is shared by every replica, so a lockout holds whichever replica serves the login.
+type LoginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginAttemptRepository(db *sqlx.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// LoginAttempts returns the failed logins of the key. Keys without failures have none.
+func (lr *LoginAttemptRepository) LoginAttempts(ctx context.Context, key string) (models.LoginAttempts, error) {
+	const op = "storage.postgresql.LoginAttempts"
+
+	stmt, err := lr.db.PreparexContext(ctx, "SELECT failures, locked_until FROM login_attempts WHERE key = $1;")
+	if err != nil {
+		return models.LoginAttempts{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var attempts models.LoginAttempts
+	err = stmt.GetContext(ctx, &attempts, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.LoginAttempts{}, nil
+		}
+
+		return models.LoginAttempts{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, nil
+}
+
+// ReserveLoginAttempt counts an attempt to log in with the key before it is checked, unless
+// the key is locked out at the given time. Earlier attempts are forgotten if the last one and
+// the end of the lockout are both before since. Reserving the attempt that reaches maxAttempts
+// locks out the key until lockUntil, so that no other attempt is checked in the meantime.
+// Reports whether the attempt was reserved.
+func (lr *LoginAttemptRepository) ReserveLoginAttempt(
+	ctx context.Context,
+	key string,
+	at time.Time,
+	since time.Time,
+	maxAttempts int,
+	lockUntil time.Time,
+) (models.LoginAttempts, bool, error) {
+	const op = "storage.postgresql.ReserveLoginAttempt"
+
+	// A locked out key doesn't match the WHERE clause, so nothing is returned.
+	stmt, err := lr.db.PreparexContext(ctx, `
+		INSERT INTO login_attempts(key, failures, last_failed_at, locked_until)
+		VALUES ($1, 1, $2, CASE WHEN $4 <= 1 THEN $5::timestamptz END)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failed_at < $3
+					AND (login_attempts.locked_until IS NULL OR login_attempts.locked_until < $3) THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			locked_until = CASE
+				WHEN login_attempts.last_failed_at < $3
+					AND (login_attempts.locked_until IS NULL OR login_attempts.locked_until < $3) THEN EXCLUDED.locked_until
+				WHEN login_attempts.failures + 1 >= $4 THEN $5::timestamptz
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		WHERE login_attempts.locked_until IS NULL OR login_attempts.locked_until <= EXCLUDED.last_failed_at
+		RETURNING failures, locked_until;`,
+	)
+	if err != nil {
+		return models.LoginAttempts{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var attempts models.LoginAttempts
+	err = stmt.GetContext(ctx, &attempts, key, at.UTC(), since.UTC(), maxAttempts, lockUntil.UTC())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			attempts, err = lr.LoginAttempts(ctx, key)
+			if err != nil {
+				return models.LoginAttempts{}, false, fmt.Errorf("%s: %w", op, err)
+			}
+
+			return attempts, false, nil
+		}
+
+		return models.LoginAttempts{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, true, nil
+}
+
+// ReleaseLoginAttempt takes back an attempt reserved with ReserveLoginAttempt that turned out
+// not to fail. The lockout taken by reaching maxAttempts is lifted with it.
+func (lr *LoginAttemptRepository) ReleaseLoginAttempt(ctx context.Context, key string, maxAttempts int) error {
+	const op = "storage.postgresql.ReleaseLoginAttempt"
+
+	stmt, err := lr.db.PreparexContext(ctx, `
+		UPDATE login_attempts SET
+			failures = failures - 1,
+			locked_until = CASE WHEN failures = $1 THEN NULL ELSE locked_until END
+		WHERE key = $2 AND failures > 0;`,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, maxAttempts, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// LockLogin locks out logins of the key until the given time.
+func (lr *LoginAttemptRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	const op = "storage.postgresql.LockLogin"
+
+	stmt, err := lr.db.PreparexContext(ctx, "UPDATE login_attempts SET locked_until = $1 WHERE key = $2;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, until.UTC(), key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ResetLoginAttempts forgets the failed logins of the key and lifts its lockout.
+func (lr *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	const op = "storage.postgresql.ResetLoginAttempts"
+
+	stmt, err := lr.db.PreparexContext(ctx, "DELETE FROM login_attempts WHERE key = $1;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredLoginAttempts removes keys whose last failure and lockout both ended before the given time.
+// Returns the number of removed keys.
+func (lr *LoginAttemptRepository) DeleteExpiredLoginAttempts(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.DeleteExpiredLoginAttempts"
+
+	stmt, err := lr.db.PreparexContext(
+		ctx,
+		"DELETE FROM login_attempts WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < $1);",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type PasswordResetRepository struct {
+	db *sqlx.DB
+}
+
+func NewPasswordResetRepository(db *sqlx.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// SaveResetToken stores the hash of a password reset token of the user.
+func (pr *PasswordResetRepository) SaveResetToken(
+	ctx context.Context,
+	userID int64,
+	tokenHash string,
+	expiry time.Time,
+) error {
+	const op = "storage.postgresql.SaveResetToken"
+
+	stmt, err := pr.db.PreparexContext(
+		ctx,
+		"INSERT INTO password_reset_tokens(token_hash, user_id, expiry) VALUES ($1, $2, $3);",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, tokenHash, userID, expiry.UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ConsumeResetToken deletes the token if it hasn't expired by now and returns the id of its user.
+// Returns storage.ErrResetTokenNotFound if there is no such token.
+func (pr *PasswordResetRepository) ConsumeResetToken(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	const op = "storage.postgresql.ConsumeResetToken"
+
+	stmt, err := pr.db.PreparexContext(
+		ctx,
+		"DELETE FROM password_reset_tokens WHERE token_hash = $1 AND expiry > $2 RETURNING user_id;",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var userID int64
+	err = stmt.QueryRowxContext(ctx, tokenHash, now.UTC()).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrResetTokenNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+// DeleteUserResetTokens removes every outstanding reset token of the user.
+func (pr *PasswordResetRepository) DeleteUserResetTokens(ctx context.Context, userID int64) error {
+	const op = "storage.postgresql.DeleteUserResetTokens"
+
+	stmt, err := pr.db.PreparexContext(ctx, "DELETE FROM password_reset_tokens WHERE user_id = $1;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredResetTokens removes tokens that expired before the given time.
+// Returns the number of removed tokens.
+func (pr *PasswordResetRepository) DeleteExpiredResetTokens(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.DeleteExpiredResetTokens"
+
+	stmt, err := pr.db.PreparexContext(ctx, "DELETE FROM password_reset_tokens WHERE expiry < $1;")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/diproducts/application-tracker-go/internal/config"
 	"github.com/jmoiron/sqlx"
@@ -29,3 +30,17 @@ func InitDB(dbCfg *config.Database) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// checkAffected returns notFoundErr if the statement didn't touch any rows.
+func checkAffected(op string, res sql.Result, notFoundErr error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, notFoundErr)
+	}
+
+	return nil
+}
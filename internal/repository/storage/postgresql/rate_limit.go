@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// RateLimitRepository keeps the token buckets of rate limited keys. The buckets are shared
+// by every replica, so a client is limited the same whichever replica serves it.
+type RateLimitRepository struct {
+	db *sqlx.DB
+}
+
+func NewRateLimitRepository(db *sqlx.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// refilledTokens are the tokens of the existing bucket refilled up to now. $2 is the capacity,
+// $3 the time of the request and $4 the number of tokens refilled per second.
+const refilledTokens = `LEAST(
+	$2::double precision,
+	b.tokens + GREATEST(EXTRACT(EPOCH FROM $3::timestamptz - b.updated)::double precision, 0) * $4::double precision
+)`
+
+// TakeToken takes a token from the bucket of the key if it has one. Keys without a bucket start with a full one.
+// The bucket is refilled and taken from by a single upsert, so concurrent requests of the key queue on its row.
+func (rr *RateLimitRepository) TakeToken(
+	ctx context.Context,
+	key string,
+	limit models.RateLimit,
+	now time.Time,
+) (models.RateLimitResult, error) {
+	const op = "storage.postgresql.TakeToken"
+
+	stmt, err := rr.db.PreparexContext(
+		ctx,
+		`INSERT INTO rate_limit_buckets AS b (key, tokens, updated, allowed)
+		VALUES ($1, $2::double precision - 1, $3, TRUE)
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = `+refilledTokens+` - CASE WHEN `+refilledTokens+` >= 1 THEN 1 ELSE 0 END,
+			updated = $3,
+			allowed = `+refilledTokens+` >= 1
+		RETURNING tokens, updated, allowed;`,
+	)
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var bucket struct {
+		models.TokenBucket
+		Allowed bool `db:"allowed"`
+	}
+	err = stmt.GetContext(
+		ctx,
+		&bucket,
+		key,
+		float64(limit.Capacity()),
+		now.UTC(),
+		float64(limit.Requests)/limit.Period.Seconds(),
+	)
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bucket.Result(limit, bucket.Allowed), nil
+}
+
+// DeleteIdleBuckets removes buckets that weren't used since the given time.
+// Returns the number of removed buckets.
+func (rr *RateLimitRepository) DeleteIdleBuckets(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.DeleteIdleBuckets"
+
+	stmt, err := rr.db.PreparexContext(ctx, "DELETE FROM rate_limit_buckets WHERE updated < $1;")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
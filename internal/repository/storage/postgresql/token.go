@@ -2,9 +2,9 @@ package postgresql
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
 	"github.com/diproducts/application-tracker-go/internal/repository/storage"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -27,7 +27,7 @@ func (ts *TokenRepository) BlacklistToken(ctx context.Context, userID int64, tok
 
 	stmt, err := ts.db.PrepareContext(
 		ctx,
-		"INSERT INTO token_blacklist(user_id, token_id, expiry) VALUES(?, ?, ?)",
+		"INSERT INTO token_blacklist(user_id, token_id, expiry) VALUES($1, $2, $3)",
 	)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -35,7 +35,7 @@ func (ts *TokenRepository) BlacklistToken(ctx context.Context, userID int64, tok
 
 	_, err = stmt.ExecContext(ctx, userID, tokenID, expiry)
 	if err != nil {
-		var pqError pq.Error
+		var pqError *pq.Error
 
 		if errors.As(err, &pqError) && pqError.Code == uniqueViolationErrorCode {
 			return fmt.Errorf("%s: %w", op, storage.ErrTokenAlreadyBlacklisted)
@@ -49,22 +49,226 @@ func (ts *TokenRepository) BlacklistToken(ctx context.Context, userID int64, tok
 
 // IsBlacklisted checks if the token is blacklisted.
 func (ts *TokenRepository) IsBlacklisted(ctx context.Context, tokenID string) (bool, error) {
-	const op = "storage.postgresql.BlacklistToken"
+	const op = "storage.postgresql.IsBlacklisted"
 
-	stmt, err := ts.db.PrepareContext(ctx, "SELECT user_id FROM token_blacklist WHERE token_id=?")
+	stmt, err := ts.db.PrepareContext(ctx, "SELECT EXISTS(SELECT 1 FROM token_blacklist WHERE token_id = $1)")
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var userID string
-	err = stmt.QueryRowContext(ctx, tokenID).Scan(&userID)
+	var blacklisted bool
+	err = stmt.QueryRowContext(ctx, tokenID).Scan(&blacklisted)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, nil
-		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
 
+	return blacklisted, nil
+}
+
+// SaveFamily stores a new token family for the user, the session of a login.
+func (ts *TokenRepository) SaveFamily(ctx context.Context, session models.Session) error {
+	const op = "storage.postgresql.SaveFamily"
+
+	stmt, err := ts.db.PrepareContext(
+		ctx,
+		"INSERT INTO token_families(id, user_id, user_agent, ip, created, last_used_at) VALUES($1, $2, $3, $4, $5, $6)",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(
+		ctx,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IP,
+		session.Created,
+		session.LastUsedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UseFamily records that a refresh token of the family was issued to the client at the given time.
+func (ts *TokenRepository) UseFamily(ctx context.Context, familyID string, client models.Client, at time.Time) error {
+	const op = "storage.postgresql.UseFamily"
+
+	stmt, err := ts.db.PrepareContext(
+		ctx,
+		"UPDATE token_families SET user_agent = $1, ip = $2, last_used_at = $3 WHERE id = $4",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, client.UserAgent, client.IP, at, familyID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IsFamilyRevoked checks if the token family was revoked.
+// Unknown families are reported as revoked.
+func (ts *TokenRepository) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	const op = "storage.postgresql.IsFamilyRevoked"
+
+	stmt, err := ts.db.PrepareContext(
+		ctx,
+		"SELECT NOT EXISTS(SELECT 1 FROM token_families WHERE id = $1 AND revoked_at IS NULL)",
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var revoked bool
+	err = stmt.QueryRowContext(ctx, familyID).Scan(&revoked)
+	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return true, nil
+	return revoked, nil
+}
+
+// RevokeFamily marks the token family as revoked so none of its tokens can be used anymore.
+func (ts *TokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	const op = "storage.postgresql.RevokeFamily"
+
+	stmt, err := ts.db.PrepareContext(
+		ctx,
+		"UPDATE token_families SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, familyID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeUserFamilies revokes every token family of the user.
+func (ts *TokenRepository) RevokeUserFamilies(ctx context.Context, userID int64) error {
+	const op = "storage.postgresql.RevokeUserFamilies"
+
+	stmt, err := ts.db.PrepareContext(
+		ctx,
+		"UPDATE token_families SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredTokens removes blacklist entries of tokens that expired before the given time.
+// Returns the number of removed entries.
+func (ts *TokenRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.DeleteExpiredTokens"
+
+	stmt, err := ts.db.PrepareContext(ctx, "DELETE FROM token_blacklist WHERE expiry < $1")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+// Sessions returns the token families of the user that aren't revoked and were
+// used at or after since, most recently used first.
+func (ts *TokenRepository) Sessions(ctx context.Context, userID int64, since time.Time) ([]models.Session, error) {
+	const op = "storage.postgresql.Sessions"
+
+	sessions := []models.Session{}
+	err := ts.db.SelectContext(
+		ctx,
+		&sessions,
+		`SELECT id, user_id, user_agent, ip, created, last_used_at FROM token_families
+		WHERE user_id = $1 AND revoked_at IS NULL AND last_used_at >= $2
+		ORDER BY last_used_at DESC, id`,
+		userID,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes the token family with the given id owned by userID.
+// Returns storage.ErrSessionNotFound if there is no such family or it is already revoked.
+func (ts *TokenRepository) RevokeSession(ctx context.Context, userID int64, id string) error {
+	const op = "storage.postgresql.RevokeSession"
+
+	stmt, err := ts.db.PrepareContext(
+		ctx,
+		"UPDATE token_families SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// DeleteExpiredFamilies removes token families last used before the given time.
+// Returns the number of removed families.
+func (ts *TokenRepository) DeleteExpiredFamilies(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.DeleteExpiredFamilies"
+
+	stmt, err := ts.db.PrepareContext(ctx, "DELETE FROM token_families WHERE last_used_at < $1")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/diproducts/application-tracker-go/internal/repository/storage"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
+	"time"
 )
 
 type UserRepository struct {
@@ -24,13 +25,13 @@ func (ur *UserRepository) SaveUser(ctx context.Context, user *models.User) (int6
 
 	stmt, err := ur.db.PreparexContext(
 		ctx,
-		"INSERT INTO users(email, password, name) VALUES ($1, $2, $3) RETURNING id;",
+		"INSERT INTO users(email, password, name, locale) VALUES ($1, $2, $3, $4) RETURNING id;",
 	)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowxContext(ctx, user.Email, user.HashedPassword, user.Name)
+	row := stmt.QueryRowxContext(ctx, user.Email, user.HashedPassword, user.Name, user.Locale)
 
 	// TODO: fix incrementing if even on error
 	// right now postgres increments id even if the user already exists
@@ -50,19 +51,126 @@ func (ur *UserRepository) SaveUser(ctx context.Context, user *models.User) (int6
 func (ur *UserRepository) User(ctx context.Context, email string) (models.User, error) {
 	const op = "storage.postgresql.User"
 
-	stmt, err := ur.db.PreparexContext(ctx, "SELECT * FROM users WHERE email = $1;")
+	user, err := ur.user(ctx, "email = $1", email)
 	if err != nil {
 		return models.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	return user, nil
+}
+
+func (ur *UserRepository) UserByID(ctx context.Context, id int64) (models.User, error) {
+	const op = "storage.postgresql.UserByID"
+
+	user, err := ur.user(ctx, "id = $1", id)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// VerifyUser marks the email of the user as verified if it is still the given one
+// and wasn't verified yet. Returns storage.ErrUserNotFound otherwise.
+func (ur *UserRepository) VerifyUser(ctx context.Context, id int64, email string, at time.Time) error {
+	const op = "storage.postgresql.VerifyUser"
+
+	stmt, err := ur.db.PreparexContext(
+		ctx,
+		"UPDATE users SET verified_at = $1 WHERE id = $2 AND email = $3 AND verified_at IS NULL;",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at.UTC(), id, email)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// UpdatePassword replaces the password hash of the user.
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id int64, hashedPassword string) error {
+	const op = "storage.postgresql.UpdatePassword"
+
+	stmt, err := ur.db.PreparexContext(ctx, "UPDATE users SET password = $1 WHERE id = $2;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, hashedPassword, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// UpdateUser saves the email, name, locale and verification time of the user.
+func (ur *UserRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	const op = "storage.postgresql.UpdateUser"
+
+	stmt, err := ur.db.PreparexContext(
+		ctx,
+		"UPDATE users SET email = $1, name = $2, locale = $3, verified_at = $4 WHERE id = $5;",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var verifiedAt *time.Time
+	if user.VerifiedAt != nil {
+		at := user.VerifiedAt.UTC()
+		verifiedAt = &at
+	}
+
+	res, err := stmt.ExecContext(ctx, user.Email, user.Name, user.Locale, verifiedAt, user.ID)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code == uniqueViolationErrorCode {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserAlreadyExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// DeleteUser deletes the user. Applications, phases and tokens of the user
+// are deleted by the database cascade.
+func (ur *UserRepository) DeleteUser(ctx context.Context, id int64) error {
+	const op = "storage.postgresql.DeleteUser"
+
+	stmt, err := ur.db.PreparexContext(ctx, "DELETE FROM users WHERE id = $1;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// user returns the single user matching the where condition.
+func (ur *UserRepository) user(ctx context.Context, where string, arg any) (models.User, error) {
+	stmt, err := ur.db.PreparexContext(ctx, "SELECT "+userColumns+" FROM users WHERE "+where+";")
+	if err != nil {
+		return models.User{}, err
+	}
+
 	var user dbUser
-	err = stmt.Get(&user, email)
+	err = stmt.GetContext(ctx, &user, arg)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+			return models.User{}, storage.ErrUserNotFound
 		}
 
-		return models.User{}, fmt.Errorf("%s: %w", op, err)
+		return models.User{}, err
 	}
 
 	return models.User{
@@ -70,12 +178,18 @@ func (ur *UserRepository) User(ctx context.Context, email string) (models.User,
 		HashedPassword: user.Password,
 		Email:          user.Email,
 		Name:           user.Name,
+		Locale:         user.Locale,
+		VerifiedAt:     user.VerifiedAt,
 	}, nil
 }
 
+const userColumns = "id, password, email, COALESCE(name, '') AS name, locale, verified_at"
+
 type dbUser struct {
-	ID       int64  `db:"id"`
-	Password string `db:"password"`
-	Email    string `db:"email"`
-	Name     string `db:"name"`
+	ID         int64      `db:"id"`
+	Password   string     `db:"password"`
+	Email      string     `db:"email"`
+	Name       string     `db:"name"`
+	Locale     string     `db:"locale"`
+	VerifiedAt *time.Time `db:"verified_at"`
 }
@@ -0,0 +1,250 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// currentPhase selects the name of the latest phase of the application timeline.
+const currentPhase = `(SELECT p.name FROM application_phases p WHERE p.application_id = applications.id
+	ORDER BY p.date DESC, p.position DESC, p.id DESC LIMIT 1)`
+
+// defaultApplicationSort lists the newest applications first.
+var defaultApplicationSort = []models.SortOrder{{Field: models.ApplicationSortCreated, Desc: true}}
+
+// applicationField describes how a sortable field is ordered in sql and in memory
+// and how its value is stored in a cursor.
+type applicationField struct {
+	value   func(app *models.Application) any
+	compare func(a, b *models.Application) int
+	encode  func(app *models.Application) string
+	decode  func(app *models.Application, s string) error
+}
+
+var applicationFields = map[string]applicationField{
+	models.ApplicationSortCompanyName: {
+		value:   func(app *models.Application) any { return app.CompanyName },
+		compare: func(a, b *models.Application) int { return strings.Compare(a.CompanyName, b.CompanyName) },
+		encode:  func(app *models.Application) string { return app.CompanyName },
+		decode: func(app *models.Application, s string) error {
+			app.CompanyName = s
+			return nil
+		},
+	},
+	models.ApplicationSortPosition: {
+		value:   func(app *models.Application) any { return app.Position },
+		compare: func(a, b *models.Application) int { return strings.Compare(a.Position, b.Position) },
+		encode:  func(app *models.Application) string { return app.Position },
+		decode: func(app *models.Application, s string) error {
+			app.Position = s
+			return nil
+		},
+	},
+	models.ApplicationSortOfferedSalary: {
+		value:   func(app *models.Application) any { return app.OfferedSalary },
+		compare: func(a, b *models.Application) int { return cmp.Compare(a.OfferedSalary, b.OfferedSalary) },
+		encode:  func(app *models.Application) string { return strconv.Itoa(app.OfferedSalary) },
+		decode: func(app *models.Application, s string) (err error) {
+			app.OfferedSalary, err = strconv.Atoi(s)
+			return err
+		},
+	},
+	models.ApplicationSortCreated: {
+		value:   func(app *models.Application) any { return app.Created },
+		compare: func(a, b *models.Application) int { return a.Created.Compare(b.Created) },
+		encode:  func(app *models.Application) string { return app.Created.Format(time.RFC3339Nano) },
+		decode: func(app *models.Application, s string) (err error) {
+			app.Created, err = time.Parse(time.RFC3339Nano, s)
+			return err
+		},
+	},
+	models.ApplicationSortLastModified: {
+		value:   func(app *models.Application) any { return app.LastModified },
+		compare: func(a, b *models.Application) int { return a.LastModified.Compare(b.LastModified) },
+		encode:  func(app *models.Application) string { return app.LastModified.Format(time.RFC3339Nano) },
+		decode: func(app *models.Application, s string) (err error) {
+			app.LastModified, err = time.Parse(time.RFC3339Nano, s)
+			return err
+		},
+	},
+}
+
+// isApplicationSortField reports whether applications can be sorted by field.
+func isApplicationSortField(field string) bool {
+	_, ok := applicationFields[field]
+	return ok
+}
+
+// Applications builds the query of a page of applications owned by ownerID.
+// It selects one row more than q.Limit so ApplicationPage can tell whether there is a next page.
+// The name filters use lower, see Contains.
+func Applications(columns, lower string, ownerID int64, q models.ApplicationQuery) (*Select, error) {
+	anchor, err := ApplicationAnchor(q)
+	if err != nil {
+		return nil, err
+	}
+
+	sort := ApplicationSort(q.Sort)
+
+	s := NewSelect(columns, "applications").Where("owner_id = ?", ownerID)
+
+	f := q.Filter
+	if f.CompanyName != "" {
+		s.Where(Contains(lower, "company_name", f.CompanyName))
+	}
+	if f.Position != "" {
+		s.Where(Contains(lower, "position", f.Position))
+	}
+	if f.MinSalary != nil {
+		s.Where("offered_salary >= ?", *f.MinSalary)
+	}
+	if f.MaxSalary != nil {
+		s.Where("offered_salary <= ?", *f.MaxSalary)
+	}
+	if f.CreatedFrom != nil {
+		s.Where("created >= ?", f.CreatedFrom.UTC())
+	}
+	if f.CreatedTo != nil {
+		s.Where("created < ?", f.CreatedTo.UTC())
+	}
+	if f.ModifiedFrom != nil {
+		s.Where("last_modified >= ?", f.ModifiedFrom.UTC())
+	}
+	if f.ModifiedTo != nil {
+		s.Where("last_modified < ?", f.ModifiedTo.UTC())
+	}
+	if f.Phase != "" {
+		s.Where(currentPhase+" = ?", f.Phase)
+	}
+
+	columnsOrder := make([]Column, 0, len(sort)+1)
+	for _, o := range sort {
+		columnsOrder = append(columnsOrder, Column{Expr: o.Field, Desc: o.Desc})
+	}
+	columnsOrder = append(columnsOrder, Column{Expr: "id", Desc: sort[len(sort)-1].Desc})
+
+	if anchor != nil {
+		values := make([]any, 0, len(columnsOrder))
+		for _, o := range sort {
+			values = append(values, applicationFields[o.Field].value(anchor))
+		}
+		values = append(values, anchor.ID)
+
+		cond, args := After(columnsOrder, values)
+		s.Where(cond, args...)
+	}
+
+	return s.OrderBy(columnsOrder...).Limit(q.Limit + 1), nil
+}
+
+// ApplicationSort returns sort or the default sort if it is empty.
+func ApplicationSort(sort []models.SortOrder) []models.SortOrder {
+	if len(sort) == 0 {
+		return defaultApplicationSort
+	}
+
+	return sort
+}
+
+// ApplicationAnchor checks q.Sort and decodes q.Cursor into the last application of the previous page.
+// Only the id and the sort fields of the anchor are set. Returns nil if there is no cursor.
+func ApplicationAnchor(q models.ApplicationQuery) (*models.Application, error) {
+	const op = "storage.query.ApplicationAnchor"
+
+	sort := ApplicationSort(q.Sort)
+
+	for _, o := range sort {
+		if !isApplicationSortField(o.Field) {
+			return nil, fmt.Errorf("%s: unsupported sort field %q", op, o.Field)
+		}
+	}
+
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	c, err := decodeCursor(q.Cursor)
+	if err != nil || c.Sort != sortKey(sort) || len(c.Values) != len(sort)+1 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrInvalidCursor)
+	}
+
+	anchor := &models.Application{}
+	for i, o := range sort {
+		if err := applicationFields[o.Field].decode(anchor, c.Values[i]); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrInvalidCursor)
+		}
+	}
+
+	anchor.ID, err = strconv.ParseInt(c.Values[len(sort)], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrInvalidCursor)
+	}
+
+	return anchor, nil
+}
+
+// CompareApplications compares applications by sort with id as the tie-breaker,
+// matching the order of the query built by Applications.
+func CompareApplications(a, b *models.Application, sort []models.SortOrder) int {
+	sort = ApplicationSort(sort)
+
+	for _, o := range sort {
+		c := applicationFields[o.Field].compare(a, b)
+		if o.Desc {
+			c = -c
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	c := cmp.Compare(a.ID, b.ID)
+	if sort[len(sort)-1].Desc {
+		c = -c
+	}
+
+	return c
+}
+
+// ApplicationPage cuts apps, fetched with one extra row, to q.Limit
+// and creates the cursor of the next page if there is one.
+func ApplicationPage(apps []models.Application, q models.ApplicationQuery) models.ApplicationPage {
+	if len(apps) <= q.Limit {
+		return models.ApplicationPage{Applications: apps}
+	}
+
+	apps = apps[:q.Limit]
+	last := &apps[len(apps)-1]
+	sort := ApplicationSort(q.Sort)
+
+	values := make([]string, 0, len(sort)+1)
+	for _, o := range sort {
+		values = append(values, applicationFields[o.Field].encode(last))
+	}
+	values = append(values, strconv.FormatInt(last.ID, 10))
+
+	return models.ApplicationPage{
+		Applications: apps,
+		NextCursor:   encodeCursor(cursor{Sort: sortKey(sort), Values: values}),
+	}
+}
+
+// sortKey identifies the sort a cursor was created for.
+func sortKey(sort []models.SortOrder) string {
+	keys := make([]string, 0, len(sort))
+	for _, o := range sort {
+		if o.Desc {
+			keys = append(keys, "-"+o.Field)
+		} else {
+			keys = append(keys, o.Field)
+		}
+	}
+
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,33 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// cursor is the decoded form of an opaque page cursor: the sort it was
+// created for and the sort keys of the last row of the previous page.
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, err
+	}
+
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return cursor{}, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,133 @@
+package query
+
+import (
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+// Select builds a SELECT statement with ? placeholders.
+// Build rebinds them to the placeholder style of the database driver,
+// so the same query can be shared by every sql backend.
+type Select struct {
+	columns string
+	from    string
+	where   []string
+	args    []any
+	orderBy []Column
+	limit   int
+}
+
+// Column is a sort key of an ORDER BY clause or of a keyset condition.
+type Column struct {
+	Expr string
+	Desc bool
+}
+
+func NewSelect(columns, from string) *Select {
+	return &Select{columns: columns, from: from}
+}
+
+// Where adds a condition joined to the others with AND. cond uses ? placeholders for args.
+func (s *Select) Where(cond string, args ...any) *Select {
+	s.where = append(s.where, cond)
+	s.args = append(s.args, args...)
+
+	return s
+}
+
+func (s *Select) OrderBy(columns ...Column) *Select {
+	s.orderBy = append(s.orderBy, columns...)
+
+	return s
+}
+
+func (s *Select) Limit(n int) *Select {
+	s.limit = n
+
+	return s
+}
+
+// Build returns the statement and its arguments. bindType is a sqlx bind type, e.g. sqlx.DOLLAR.
+func (s *Select) Build(bindType int) (string, []any) {
+	var b strings.Builder
+
+	b.WriteString("SELECT ")
+	b.WriteString(s.columns)
+	b.WriteString(" FROM ")
+	b.WriteString(s.from)
+
+	if len(s.where) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(s.where, " AND "))
+	}
+
+	if len(s.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+
+		for i, c := range s.orderBy {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(c.Expr)
+			if c.Desc {
+				b.WriteString(" DESC")
+			}
+		}
+	}
+
+	args := append([]any{}, s.args...)
+	if s.limit > 0 {
+		b.WriteString(" LIMIT ?")
+		args = append(args, s.limit)
+	}
+
+	b.WriteString(";")
+
+	return sqlx.Rebind(bindType, b.String()), args
+}
+
+// Functions lowering the case of text for Contains. The built-in LOWER of SQLite
+// only folds ASCII letters, so the sqlite package registers UnicodeLower instead.
+const (
+	Lower        = "LOWER"
+	UnicodeLower = "unicode_lower"
+)
+
+// Contains returns a case-insensitive substring condition on expr.
+// lower is the function folding the case of expr like strings.ToLower does.
+func Contains(lower, expr, substr string) (string, any) {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+	return lower + "(" + expr + `) LIKE ? ESCAPE '\'`, "%" + replacer.Replace(strings.ToLower(substr)) + "%"
+}
+
+// After returns a keyset condition matching the rows that follow the row
+// with the given values when ordered by columns.
+func After(columns []Column, values []any) (string, []any) {
+	var (
+		terms []string
+		args  []any
+	)
+
+	for i, c := range columns {
+		var term []string
+
+		for j := range i {
+			term = append(term, columns[j].Expr+" = ?")
+			args = append(args, values[j])
+		}
+
+		op := " > ?"
+		if c.Desc {
+			op = " < ?"
+		}
+
+		term = append(term, c.Expr+op)
+		args = append(args, values[i])
+
+		terms = append(terms, "("+strings.Join(term, " AND ")+")")
+	}
+
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
@@ -0,0 +1,41 @@
+package query_test
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/query"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelect_Build(t *testing.T) {
+	s := query.NewSelect("id, name", "users").
+		Where("owner_id = ?", 1).
+		Where(query.Contains(query.Lower, "name", "50%_off")).
+		OrderBy(query.Column{Expr: "created", Desc: true}, query.Column{Expr: "id"}).
+		Limit(10)
+
+	stmt, args := s.Build(sqlx.DOLLAR)
+	assert.Equal(
+		t,
+		`SELECT id, name FROM users WHERE owner_id = $1 AND LOWER(name) LIKE $2 ESCAPE '\' ORDER BY created DESC, id LIMIT $3;`,
+		stmt,
+	)
+	assert.Equal(t, []any{1, `%50\%\_off%`, 10}, args)
+
+	stmt, _ = s.Build(sqlx.QUESTION)
+	assert.Contains(t, stmt, "owner_id = ? AND")
+}
+
+func TestAfter(t *testing.T) {
+	cond, args := query.After(
+		[]query.Column{{Expr: "salary", Desc: true}, {Expr: "name"}, {Expr: "id"}},
+		[]any{100, "acme", 7},
+	)
+
+	assert.Equal(
+		t,
+		"((salary < ?) OR (salary = ? AND name > ?) OR (salary = ? AND name = ? AND id > ?))",
+		cond,
+	)
+	assert.Equal(t, []any{100, 100, "acme", 100, "acme", 7}, args)
+}
@@ -0,0 +1,160 @@
+package query
+
+import (
+	"cmp"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"html"
+	"slices"
+	"strings"
+)
+
+const (
+	highlightStart = "<mark>"
+	highlightStop  = "</mark>"
+
+	snippetWordsBefore = 5
+	snippetWords       = 20
+)
+
+// searchField is a searchable text field of an application. Weights follow
+// the default ts_rank weights of the A, B and C labels used by postgres.
+type searchField struct {
+	column string
+	weight float64
+	value  func(app *models.Application) string
+}
+
+var searchFields = []searchField{
+	{"company_name", 1.0, func(app *models.Application) string { return app.CompanyName }},
+	{"position", 1.0, func(app *models.Application) string { return app.Position }},
+	{"job_description", 0.4, func(app *models.Application) string { return app.JobDescription }},
+	{"contacts", 0.2, func(app *models.Application) string { return app.Contacts }},
+	{"cover_letter", 0.2, func(app *models.Application) string { return app.CoverLetter }},
+}
+
+// SearchTerms splits a search query into lower-cased terms for the LIKE based search.
+// Web search syntax is not supported: quotes are dropped and negated terms are ignored.
+func SearchTerms(q string) []string {
+	var terms []string
+
+	for _, term := range strings.Fields(strings.ToLower(q)) {
+		term = strings.Trim(term, `"'`)
+		if term == "" || term == "or" || strings.HasPrefix(term, "-") {
+			continue
+		}
+
+		terms = append(terms, term)
+	}
+
+	return terms
+}
+
+// SearchApplications builds the LIKE based query of applications owned by ownerID
+// containing every term in any of the searchable fields, see Contains for lower.
+// Matches are ranked afterwards by SearchHits.
+func SearchApplications(columns, lower string, ownerID int64, terms []string) *Select {
+	s := NewSelect(columns, "applications").Where("owner_id = ?", ownerID)
+
+	for _, term := range terms {
+		conds := make([]string, 0, len(searchFields))
+		args := make([]any, 0, len(searchFields))
+
+		for _, f := range searchFields {
+			cond, arg := Contains(lower, f.column, term)
+			conds = append(conds, cond)
+			args = append(args, arg)
+		}
+
+		s.Where("("+strings.Join(conds, " OR ")+")", args...)
+	}
+
+	return s
+}
+
+// SearchHits ranks the applications containing every term and returns
+// at most limit best hits with their snippets.
+func SearchHits(apps []models.Application, terms []string, limit int) []models.ApplicationSearchHit {
+	hits := make([]models.ApplicationSearchHit, 0)
+
+	for _, app := range apps {
+		if hit, ok := searchHit(&app, terms); ok {
+			hits = append(hits, hit)
+		}
+	}
+
+	slices.SortFunc(hits, func(a, b models.ApplicationSearchHit) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+func searchHit(app *models.Application, terms []string) (models.ApplicationSearchHit, bool) {
+	var (
+		rank  float64
+		texts = make([]string, 0, len(searchFields))
+	)
+
+	for _, term := range terms {
+		found := false
+
+		for _, f := range searchFields {
+			if n := strings.Count(strings.ToLower(f.value(app)), term); n > 0 {
+				rank += f.weight * float64(n)
+				found = true
+			}
+		}
+
+		if !found {
+			return models.ApplicationSearchHit{}, false
+		}
+	}
+
+	for _, f := range searchFields {
+		if text := f.value(app); text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	return models.ApplicationSearchHit{
+		Application: *app,
+		Rank:        rank,
+		Snippet:     snippet(strings.Join(texts, " "), terms),
+	}, true
+}
+
+// snippet returns the HTML-escaped words around the first match in text
+// with matching words highlighted.
+func snippet(text string, terms []string) string {
+	words := strings.Fields(text)
+
+	matches := func(word string) bool {
+		word = strings.ToLower(word)
+
+		return slices.ContainsFunc(terms, func(term string) bool { return strings.Contains(word, term) })
+	}
+
+	first := slices.IndexFunc(words, matches)
+	start := max(0, first-snippetWordsBefore)
+	end := min(len(words), start+snippetWords)
+
+	fragment := make([]string, 0, end-start)
+	for _, word := range words[start:end] {
+		escaped := html.EscapeString(word)
+		if matches(word) {
+			escaped = highlightStart + escaped + highlightStop
+		}
+
+		fragment = append(fragment, escaped)
+	}
+
+	return strings.Join(fragment, " ")
+}
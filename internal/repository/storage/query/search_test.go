@@ -0,0 +1,28 @@
+package query_test
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSearchHits_EscapesSnippet(t *testing.T) {
+	apps := []models.Application{{
+		ID:             1,
+		CompanyName:    "Ünicode & Co",
+		JobDescription: `<script>alert("ünicode")</script> job`,
+	}}
+
+	terms := query.SearchTerms("ÜNICODE")
+	assert.Equal(t, []string{"ünicode"}, terms)
+
+	hits := query.SearchHits(apps, terms, 10)
+	require.Len(t, hits, 1)
+	assert.Equal(
+		t,
+		`<mark>Ünicode</mark> &amp; Co <mark>&lt;script&gt;alert(&#34;ünicode&#34;)&lt;/script&gt;</mark> job`,
+		hits[0].Snippet,
+	)
+}
@@ -0,0 +1,180 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/query"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type ApplicationRepository struct {
+	db *sqlx.DB
+}
+
+func NewApplicationRepository(db *sqlx.DB) *ApplicationRepository {
+	return &ApplicationRepository{db: db}
+}
+
+// SaveApplication stores a new application and returns its id.
+func (ar *ApplicationRepository) SaveApplication(ctx context.Context, app *models.Application) (int64, error) {
+	const op = "storage.sqlite.SaveApplication"
+
+	now := time.Now().UTC()
+
+	var id int64
+	err := ar.db.QueryRowxContext(
+		ctx,
+		`INSERT INTO applications(
+			company_name, position, url, job_description, contacts, cv, cover_letter, offered_salary,
+			created, last_modified, owner_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id;`,
+		app.CompanyName,
+		app.Position,
+		app.Url,
+		app.JobDescription,
+		app.Contacts,
+		app.Cv,
+		app.CoverLetter,
+		app.OfferedSalary,
+		now,
+		now,
+		app.OwnerID,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Application returns the application with the given id owned by ownerID.
+func (ar *ApplicationRepository) Application(ctx context.Context, id, ownerID int64) (models.Application, error) {
+	const op = "storage.sqlite.Application"
+
+	var app models.Application
+	err := ar.db.GetContext(
+		ctx,
+		&app,
+		"SELECT "+applicationColumns+" FROM applications WHERE id = ? AND owner_id = ?;",
+		id,
+		ownerID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Application{}, fmt.Errorf("%s: %w", op, storage.ErrApplicationNotFound)
+		}
+
+		return models.Application{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// Applications returns a page of applications owned by ownerID
+// matching q.Filter and ordered by q.Sort.
+func (ar *ApplicationRepository) Applications(
+	ctx context.Context,
+	ownerID int64,
+	q models.ApplicationQuery,
+) (models.ApplicationPage, error) {
+	const op = "storage.sqlite.Applications"
+
+	sel, err := query.Applications(applicationColumns, query.UnicodeLower, ownerID, q)
+	if err != nil {
+		return models.ApplicationPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, args := sel.Build(sqlx.QUESTION)
+
+	apps := make([]models.Application, 0, q.Limit+1)
+	err = ar.db.SelectContext(ctx, &apps, stmt, args...)
+	if err != nil {
+		return models.ApplicationPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return query.ApplicationPage(apps, q), nil
+}
+
+// SearchApplications returns at most limit applications owned by ownerID containing
+// every term of q, best matches first. SQLite has no full-text search of postgres,
+// so the terms are matched with LIKE and ranked in Go.
+func (ar *ApplicationRepository) SearchApplications(
+	ctx context.Context,
+	ownerID int64,
+	q string,
+	limit int,
+) ([]models.ApplicationSearchHit, error) {
+	const op = "storage.sqlite.SearchApplications"
+
+	terms := query.SearchTerms(q)
+	if len(terms) == 0 {
+		return make([]models.ApplicationSearchHit, 0), nil
+	}
+
+	stmt, args := query.SearchApplications(applicationColumns, query.UnicodeLower, ownerID, terms).Build(sqlx.QUESTION)
+
+	apps := make([]models.Application, 0)
+	err := ar.db.SelectContext(ctx, &apps, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return query.SearchHits(apps, terms, limit), nil
+}
+
+// UpdateApplication overwrites the editable fields of the application
+// identified by app.ID and app.OwnerID and bumps last_modified.
+func (ar *ApplicationRepository) UpdateApplication(ctx context.Context, app *models.Application) error {
+	const op = "storage.sqlite.UpdateApplication"
+
+	res, err := ar.db.ExecContext(
+		ctx,
+		`UPDATE applications SET
+			company_name = ?,
+			position = ?,
+			url = ?,
+			job_description = ?,
+			contacts = ?,
+			cv = ?,
+			cover_letter = ?,
+			offered_salary = ?,
+			last_modified = ?
+		WHERE id = ? AND owner_id = ?;`,
+		app.CompanyName,
+		app.Position,
+		app.Url,
+		app.JobDescription,
+		app.Contacts,
+		app.Cv,
+		app.CoverLetter,
+		app.OfferedSalary,
+		time.Now().UTC(),
+		app.ID,
+		app.OwnerID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrApplicationNotFound)
+}
+
+// DeleteApplication removes the application with the given id owned by ownerID.
+func (ar *ApplicationRepository) DeleteApplication(ctx context.Context, id, ownerID int64) error {
+	const op = "storage.sqlite.DeleteApplication"
+
+	res, err := ar.db.ExecContext(ctx, "DELETE FROM applications WHERE id = ? AND owner_id = ?;", id, ownerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrApplicationNotFound)
+}
+
+const applicationColumns = `id, company_name, position, url, job_description, contacts, cv, cover_letter,
+	offered_salary, created, last_modified, owner_id`
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+type IdentityRepository struct {
+	db *sqlx.DB
+}
+
+func NewIdentityRepository(db *sqlx.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// SaveIdentity links the external identity to its user.
+// Returns storage.ErrIdentityAlreadyExists if the identity is linked already.
+func (ir *IdentityRepository) SaveIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	const op = "storage.sqlite.SaveIdentity"
+
+	stmt, err := ir.db.PreparexContext(
+		ctx,
+		"INSERT INTO user_identities(provider, subject, user_id, email) VALUES (?, ?, ?, ?);",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, identity.Provider, identity.Subject, identity.UserID, identity.Email)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrIdentityAlreadyExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Identity returns the identity with the subject at the provider.
+func (ir *IdentityRepository) Identity(ctx context.Context, provider, subject string) (models.UserIdentity, error) {
+	const op = "storage.sqlite.Identity"
+
+	stmt, err := ir.db.PreparexContext(
+		ctx,
+		"SELECT provider, subject, user_id, email FROM user_identities WHERE provider = ? AND subject = ?;",
+	)
+	if err != nil {
+		return models.UserIdentity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var identity models.UserIdentity
+	err = stmt.GetContext(ctx, &identity, provider, subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserIdentity{}, fmt.Errorf("%s: %w", op, storage.ErrIdentityNotFound)
+		}
+
+		return models.UserIdentity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identity, nil
+}
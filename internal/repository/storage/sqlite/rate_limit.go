@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// RateLimitRepository keeps the token buckets of rate limited keys.
+type RateLimitRepository struct {
+	db *sqlx.DB
+}
+
+func NewRateLimitRepository(db *sqlx.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// TakeToken takes a token from the bucket of the key if it has one. Keys without a bucket start with a full one.
+func (rr *RateLimitRepository) TakeToken(
+	ctx context.Context,
+	key string,
+	limit models.RateLimit,
+	now time.Time,
+) (models.RateLimitResult, error) {
+	const op = "storage.sqlite.TakeToken"
+
+	tx, err := rr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Writing first takes the write lock of the database, so concurrent
+	// requests of the key wait instead of reading the same bucket.
+	full := models.NewTokenBucket(limit, now)
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO rate_limit_buckets(key, tokens, updated) VALUES (?, ?, ?) ON CONFLICT (key) DO NOTHING;",
+		key,
+		full.Tokens,
+		full.Updated.UTC(),
+	)
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var bucket models.TokenBucket
+	err = tx.GetContext(ctx, &bucket, "SELECT tokens, updated FROM rate_limit_buckets WHERE key = ?;", key)
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	bucket, res := bucket.Take(limit, now)
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE rate_limit_buckets SET tokens = ?, updated = ?, allowed = ? WHERE key = ?;",
+		bucket.Tokens,
+		bucket.Updated.UTC(),
+		res.Allowed,
+		key,
+	)
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// DeleteIdleBuckets removes buckets that weren't used since the given time.
+// Returns the number of removed buckets.
+func (rr *RateLimitRepository) DeleteIdleBuckets(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteIdleBuckets"
+
+	stmt, err := rr.db.PreparexContext(ctx, "DELETE FROM rate_limit_buckets WHERE updated < ?;")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
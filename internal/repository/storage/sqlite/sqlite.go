@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/query"
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"strings"
+	"sync"
+)
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// registerFunctions registers the functions the queries of this package use.
+// They are registered with the driver once and apply to every connection opened later.
+func registerFunctions() error {
+	registerOnce.Do(func() {
+		registerErr = sqlite.RegisterDeterministicScalarFunction(query.UnicodeLower, 1, unicodeLower)
+	})
+
+	return registerErr
+}
+
+// unicodeLower folds the case of text like strings.ToLower, so case-insensitive
+// filters and search match the lower-cased terms built in Go.
+func unicodeLower(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	switch v := args[0].(type) {
+	case string:
+		return strings.ToLower(v), nil
+	case []byte:
+		return strings.ToLower(string(v)), nil
+	default:
+		return v, nil
+	}
+}
+
+func InitDB(dbCfg *config.Database) (*sqlx.DB, error) {
+	const op = "storage.sqlite.InitDB"
+
+	if err := registerFunctions(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	db, err := sqlx.Open("sqlite", dbCfg.SQLiteDSN())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return db, nil
+}
+
+// isUniqueViolation reports whether err is caused by a UNIQUE or PRIMARY KEY constraint.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	code := sqliteErr.Code()
+
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// checkAffected returns notFoundErr if the statement didn't touch any rows.
+func checkAffected(op string, res sql.Result, notFoundErr error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, notFoundErr)
+	}
+
+	return nil
+}
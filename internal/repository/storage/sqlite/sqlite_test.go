@@ -0,0 +1,281 @@
+package sqlite_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/config"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/migrator"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/sqlite"
+	"github.com/diproducts/application-tracker-go/migrations"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newDB opens a migrated database in a temporary file.
+func newDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := sqlite.InitDB(&config.Database{
+		Driver: config.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "tracker.db"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	fsys, err := migrations.ForDialect(migrations.DialectSQLite)
+	require.NoError(t, err)
+
+	_, err = migrator.Apply(context.Background(), db.DB, fsys, config.DriverSQLite)
+	require.NoError(t, err)
+
+	return db
+}
+
+func saveUser(t *testing.T, db *sqlx.DB, email string) int64 {
+	t.Helper()
+
+	id, err := sqlite.NewUserRepository(db).SaveUser(context.Background(), &models.User{
+		Email:          email,
+		HashedPassword: "hash",
+		Name:           "Test",
+		Locale:         "en",
+	})
+	require.NoError(t, err)
+
+	return id
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	users := sqlite.NewUserRepository(db)
+
+	id := saveUser(t, db, "a@example.com")
+
+	_, err := users.SaveUser(ctx, &models.User{Email: "a@example.com", HashedPassword: "hash"})
+	assert.ErrorIs(t, err, storage.ErrUserAlreadyExists)
+
+	user, err := users.User(ctx, "a@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+
+	require.NoError(t, users.UpdatePassword(ctx, id, "new"))
+	user, err = users.UserByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "new", user.HashedPassword)
+
+	require.NoError(t, users.DeleteUser(ctx, id))
+	_, err = users.UserByID(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func TestTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	tokens := sqlite.NewTokenRepository(db)
+
+	userID := saveUser(t, db, "a@example.com")
+	otherID := saveUser(t, db, "b@example.com")
+	now := time.Now()
+
+	expiry := now.Add(time.Hour)
+	require.NoError(t, tokens.BlacklistToken(ctx, userID, "jti", expiry))
+	assert.ErrorIs(t, tokens.BlacklistToken(ctx, userID, "jti", expiry), storage.ErrTokenAlreadyBlacklisted)
+
+	blacklisted, err := tokens.IsBlacklisted(ctx, "jti")
+	require.NoError(t, err)
+	assert.True(t, blacklisted)
+
+	for _, id := range []string{"laptop", "phone"} {
+		require.NoError(t, tokens.SaveFamily(ctx, models.Session{
+			ID:         id,
+			UserID:     userID,
+			UserAgent:  id,
+			IP:         "192.0.2.1",
+			Created:    now.Add(-time.Hour),
+			LastUsedAt: now.Add(-time.Hour),
+		}))
+	}
+
+	require.NoError(t, tokens.UseFamily(ctx, "phone", models.Client{UserAgent: "phone", IP: "192.0.2.2"}, now))
+
+	sessions, err := tokens.Sessions(ctx, userID, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "phone", sessions[0].ID)
+	assert.Equal(t, "192.0.2.2", sessions[0].IP)
+	assert.WithinDuration(t, now, sessions[0].LastUsedAt, time.Millisecond)
+
+	assert.ErrorIs(t, tokens.RevokeSession(ctx, otherID, "phone"), storage.ErrSessionNotFound)
+	require.NoError(t, tokens.RevokeSession(ctx, userID, "phone"))
+	assert.ErrorIs(t, tokens.RevokeSession(ctx, userID, "phone"), storage.ErrSessionNotFound)
+
+	revoked, err := tokens.IsFamilyRevoked(ctx, "phone")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	n, err := tokens.DeleteExpiredFamilies(ctx, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	revoked, err = tokens.IsFamilyRevoked(ctx, "laptop")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestApplicationRepositories(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	apps := sqlite.NewApplicationRepository(db)
+	phases := sqlite.NewApplicationPhaseRepository(db)
+
+	ownerID := saveUser(t, db, "a@example.com")
+	otherID := saveUser(t, db, "b@example.com")
+	now := time.Now()
+
+	appID, err := apps.SaveApplication(ctx, &models.Application{
+		CompanyName:    "Äcme",
+		Position:       "Go developer",
+		JobDescription: "Building distributed systems in Go",
+		OwnerID:        ownerID,
+		Created:        now,
+		LastModified:   now,
+	})
+	require.NoError(t, err)
+
+	_, err = apps.Application(ctx, appID, otherID)
+	assert.ErrorIs(t, err, storage.ErrApplicationNotFound)
+
+	page, err := apps.Applications(ctx, ownerID, models.ApplicationQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Applications, 1)
+	assert.Equal(t, "Äcme", page.Applications[0].CompanyName)
+
+	hits, err := apps.SearchApplications(ctx, ownerID, "distributed", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Contains(t, hits[0].Snippet, "<mark>distributed</mark>")
+
+	// LOWER folds non-ASCII letters the same way the search terms are folded.
+	hits, err = apps.SearchApplications(ctx, ownerID, "ÄCME", 10)
+	require.NoError(t, err)
+	assert.Len(t, hits, 1)
+
+	var ids []int64
+	for _, name := range []string{models.PhaseApplied, models.PhaseInterview} {
+		id, err := phases.SavePhase(ctx, ownerID, &models.ApplicationPhase{
+			Name:          name,
+			Date:          now,
+			Created:       now,
+			ApplicationID: appID,
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	// A failed reorder leaves the positions as they were.
+	err = phases.ReorderPhases(ctx, ownerID, appID, []int64{ids[1], ids[0] + 100})
+	assert.ErrorIs(t, err, storage.ErrPhaseNotFound)
+
+	require.NoError(t, phases.ReorderPhases(ctx, ownerID, appID, []int64{ids[1], ids[0]}))
+
+	list, err := phases.Phases(ctx, ownerID, appID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, models.PhaseInterview, list[0].Name)
+
+	require.NoError(t, apps.DeleteApplication(ctx, appID, ownerID))
+	_, err = phases.Phases(ctx, ownerID, appID)
+	assert.ErrorIs(t, err, storage.ErrApplicationNotFound)
+}
+
+func TestRateLimitRepository(t *testing.T) {
+	ctx := context.Background()
+	buckets := sqlite.NewRateLimitRepository(newDB(t))
+	limit := models.RateLimit{Requests: 60, Period: time.Minute, Burst: 2}
+	now := time.Now()
+
+	for i := 1; i >= 0; i-- {
+		res, err := buckets.TakeToken(ctx, "key", limit, now)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, err := buckets.TakeToken(ctx, "key", limit, now)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	res, err = buckets.TakeToken(ctx, "key", limit, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	n, err := buckets.DeleteIdleBuckets(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestLoginAttemptRepository(t *testing.T) {
+	ctx := context.Background()
+	attempts := sqlite.NewLoginAttemptRepository(newDB(t))
+	now := time.Now()
+
+	for i := 1; i <= 2; i++ {
+		got, ok, err := attempts.ReserveLoginAttempt(ctx, "ip:192.0.2.1", now, now.Add(-time.Minute), 3, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, i, got.Failures)
+		assert.Nil(t, got.LockedUntil)
+	}
+
+	// The attempt reaching the limit locks out the others while it is checked.
+	got, ok, err := attempts.ReserveLoginAttempt(ctx, "ip:192.0.2.1", now, now.Add(-time.Minute), 3, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, got.Failures)
+	require.NotNil(t, got.LockedUntil)
+
+	got, ok, err = attempts.ReserveLoginAttempt(ctx, "ip:192.0.2.1", now, now.Add(-time.Minute), 3, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 3, got.Failures)
+	require.NotNil(t, got.LockedUntil)
+
+	// Releasing it lifts the lockout.
+	require.NoError(t, attempts.ReleaseLoginAttempt(ctx, "ip:192.0.2.1", 3))
+	got, err = attempts.LoginAttempts(ctx, "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Failures)
+	assert.Nil(t, got.LockedUntil)
+
+	// Attempts older than the window are forgotten.
+	got, ok, err = attempts.ReserveLoginAttempt(ctx, "ip:192.0.2.1", now.Add(time.Hour), now.Add(time.Minute), 3, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, got.Failures)
+
+	require.NoError(t, attempts.LockLogin(ctx, "ip:192.0.2.1", now.Add(2*time.Hour)))
+	got, err = attempts.LoginAttempts(ctx, "ip:192.0.2.1")
+	require.NoError(t, err)
+	require.NotNil(t, got.LockedUntil)
+
+	require.NoError(t, attempts.ResetLoginAttempts(ctx, "ip:192.0.2.1"))
+	got, err = attempts.LoginAttempts(ctx, "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.Zero(t, got.Failures)
+}
+
+func TestInitDB_KeepsBuiltinLower(t *testing.T) {
+	db := newDB(t)
+
+	var builtin, unicode string
+	require.NoError(t, db.QueryRow(`SELECT lower('ÄB'), unicode_lower('ÄB');`).Scan(&builtin, &unicode))
+	assert.Equal(t, "Äb", builtin)
+	assert.Equal(t, "äb", unicode)
+}
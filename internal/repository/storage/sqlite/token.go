@@ -0,0 +1,235 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type TokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewTokenRepository(db *sqlx.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+// BlacklistToken stores user_id, token_id and expiry time in the token_blacklist table
+func (ts *TokenRepository) BlacklistToken(ctx context.Context, userID int64, tokenID string, expiry time.Time) error {
+	const op = "storage.sqlite.BlacklistToken"
+
+	_, err := ts.db.ExecContext(
+		ctx,
+		"INSERT INTO token_blacklist(user_id, token_id, expiry) VALUES(?, ?, ?)",
+		userID,
+		tokenID,
+		expiry.UTC(),
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrTokenAlreadyBlacklisted)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IsBlacklisted checks if the token is blacklisted.
+func (ts *TokenRepository) IsBlacklisted(ctx context.Context, tokenID string) (bool, error) {
+	const op = "storage.sqlite.IsBlacklisted"
+
+	var blacklisted bool
+	err := ts.db.QueryRowxContext(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM token_blacklist WHERE token_id = ?)",
+		tokenID,
+	).Scan(&blacklisted)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blacklisted, nil
+}
+
+// SaveFamily stores a new token family for the user, the session of a login.
+func (ts *TokenRepository) SaveFamily(ctx context.Context, session models.Session) error {
+	const op = "storage.sqlite.SaveFamily"
+
+	_, err := ts.db.ExecContext(
+		ctx,
+		"INSERT INTO token_families(id, user_id, user_agent, ip, created, last_used_at) VALUES(?, ?, ?, ?, ?, ?)",
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IP,
+		session.Created.UTC(),
+		session.LastUsedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UseFamily records that a refresh token of the family was issued to the client at the given time.
+func (ts *TokenRepository) UseFamily(ctx context.Context, familyID string, client models.Client, at time.Time) error {
+	const op = "storage.sqlite.UseFamily"
+
+	_, err := ts.db.ExecContext(
+		ctx,
+		"UPDATE token_families SET user_agent = ?, ip = ?, last_used_at = ? WHERE id = ?",
+		client.UserAgent,
+		client.IP,
+		at.UTC(),
+		familyID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IsFamilyRevoked checks if the token family was revoked.
+// Unknown families are reported as revoked.
+func (ts *TokenRepository) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	const op = "storage.sqlite.IsFamilyRevoked"
+
+	var revoked bool
+	err := ts.db.QueryRowxContext(
+		ctx,
+		"SELECT NOT EXISTS(SELECT 1 FROM token_families WHERE id = ? AND revoked_at IS NULL)",
+		familyID,
+	).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revoked, nil
+}
+
+// RevokeFamily marks the token family as revoked so none of its tokens can be used anymore.
+func (ts *TokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	const op = "storage.sqlite.RevokeFamily"
+
+	_, err := ts.db.ExecContext(
+		ctx,
+		"UPDATE token_families SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL",
+		time.Now().UTC(),
+		familyID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeUserFamilies revokes every token family of the user.
+func (ts *TokenRepository) RevokeUserFamilies(ctx context.Context, userID int64) error {
+	const op = "storage.sqlite.RevokeUserFamilies"
+
+	_, err := ts.db.ExecContext(
+		ctx,
+		"UPDATE token_families SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL",
+		time.Now().UTC(),
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredTokens removes blacklist entries of tokens that expired before the given time.
+// Returns the number of removed entries.
+func (ts *TokenRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredTokens"
+
+	res, err := ts.db.ExecContext(ctx, "DELETE FROM token_blacklist WHERE expiry < ?", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+// Sessions returns the token families of the user that aren't revoked and were
+// used at or after since, most recently used first.
+func (ts *TokenRepository) Sessions(ctx context.Context, userID int64, since time.Time) ([]models.Session, error) {
+	const op = "storage.sqlite.Sessions"
+
+	sessions := []models.Session{}
+	err := ts.db.SelectContext(
+		ctx,
+		&sessions,
+		`SELECT id, user_id, user_agent, ip, created, last_used_at FROM token_families
+		WHERE user_id = ? AND revoked_at IS NULL AND last_used_at >= ?
+		ORDER BY last_used_at DESC, id`,
+		userID,
+		since.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes the token family with the given id owned by userID.
+// Returns storage.ErrSessionNotFound if there is no such family or it is already revoked.
+func (ts *TokenRepository) RevokeSession(ctx context.Context, userID int64, id string) error {
+	const op = "storage.sqlite.RevokeSession"
+
+	res, err := ts.db.ExecContext(
+		ctx,
+		"UPDATE token_families SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL",
+		time.Now().UTC(),
+		id,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// DeleteExpiredFamilies removes token families last used before the given time.
+// Returns the number of removed families.
+func (ts *TokenRepository) DeleteExpiredFamilies(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredFamilies"
+
+	res, err := ts.db.ExecContext(ctx, "DELETE FROM token_families WHERE last_used_at < ?", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
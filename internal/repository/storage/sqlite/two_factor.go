@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepository(db *sqlx.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SaveTOTP stores a pending totp secret of the user, replacing the previous pending one.
+// Returns storage.ErrTOTPAlreadyEnabled if the user has a confirmed one.
+func (tr *TwoFactorRepository) SaveTOTP(ctx context.Context, userID int64, secret string) error {
+	const op = "storage.sqlite.SaveTOTP"
+
+	stmt, err := tr.db.PreparexContext(
+		ctx,
+		`INSERT INTO user_totp(user_id, secret) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, last_step = 0, created = CURRENT_TIMESTAMP
+		WHERE user_totp.confirmed_at IS NULL;`,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, secret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrTOTPAlreadyEnabled)
+}
+
+// TOTP returns the totp enrollment of the user, pending or confirmed.
+func (tr *TwoFactorRepository) TOTP(ctx context.Context, userID int64) (models.TOTP, error) {
+	const op = "storage.sqlite.TOTP"
+
+	stmt, err := tr.db.PreparexContext(
+		ctx,
+		"SELECT user_id, secret, confirmed_at, last_step FROM user_totp WHERE user_id = ?;",
+	)
+	if err != nil {
+		return models.TOTP{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var totp models.TOTP
+	err = stmt.GetContext(ctx, &totp, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TOTP{}, fmt.Errorf("%s: %w", op, storage.ErrTOTPNotFound)
+		}
+
+		return models.TOTP{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return totp, nil
+}
+
+// ConfirmTOTP enables the pending totp of the user and replaces the recovery codes with the given hashes.
+// Returns storage.ErrTOTPNotFound if there is no pending totp.
+func (tr *TwoFactorRepository) ConfirmTOTP(ctx context.Context, userID int64, at time.Time, codeHashes []string) error {
+	const op = "storage.sqlite.ConfirmTOTP"
+
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE user_totp SET confirmed_at = ? WHERE user_id = ? AND confirmed_at IS NULL;",
+		at.UTC(),
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkAffected(op, res, storage.ErrTOTPNotFound); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?;", userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := tx.PreparexContext(ctx, "INSERT INTO recovery_codes(user_id, code_hash) VALUES (?, ?);")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, hash := range codeHashes {
+		if _, err := stmt.ExecContext(ctx, userID, hash); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep records that a code of the time step was accepted.
+// Returns storage.ErrTOTPStepUsed if a code of this or a later step was accepted before.
+func (tr *TwoFactorRepository) UseTOTPStep(ctx context.Context, userID, step int64) error {
+	const op = "storage.sqlite.UseTOTPStep"
+
+	stmt, err := tr.db.PreparexContext(
+		ctx,
+		"UPDATE user_totp SET last_step = ? WHERE user_id = ? AND last_step < ?;",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, step, userID, step)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrTOTPStepUsed)
+}
+
+// UseRecoveryCode deletes the recovery code of the user.
+// Returns storage.ErrRecoveryCodeNotFound if there is no such code.
+func (tr *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	const op = "storage.sqlite.UseRecoveryCode"
+
+	stmt, err := tr.db.PreparexContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ? AND code_hash = ?;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrRecoveryCodeNotFound)
+}
+
+// DeleteTOTP removes the totp and the recovery codes of the user.
+// Returns storage.ErrTOTPNotFound if the user has no totp.
+func (tr *TwoFactorRepository) DeleteTOTP(ctx context.Context, userID int64) error {
+	const op = "storage.sqlite.DeleteTOTP"
+
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id = ?;", userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkAffected(op, res, storage.ErrTOTPNotFound); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?;", userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type UserRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (ur *UserRepository) SaveUser(ctx context.Context, user *models.User) (int64, error) {
+	const op = "storage.sqlite.SaveUser"
+
+	stmt, err := ur.db.PreparexContext(
+		ctx,
+		"INSERT INTO users(email, password, name, locale) VALUES (?, ?, ?, ?) RETURNING id;",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err = stmt.QueryRowxContext(ctx, user.Email, user.HashedPassword, user.Name, user.Locale).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserAlreadyExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (ur *UserRepository) User(ctx context.Context, email string) (models.User, error) {
+	const op = "storage.sqlite.User"
+
+	user, err := ur.user(ctx, "email = ?", email)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (ur *UserRepository) UserByID(ctx context.Context, id int64) (models.User, error) {
+	const op = "storage.sqlite.UserByID"
+
+	user, err := ur.user(ctx, "id = ?", id)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// VerifyUser marks the email of the user as verified if it is still the given one
+// and wasn't verified yet. Returns storage.ErrUserNotFound otherwise.
+func (ur *UserRepository) VerifyUser(ctx context.Context, id int64, email string, at time.Time) error {
+	const op = "storage.sqlite.VerifyUser"
+
+	stmt, err := ur.db.PreparexContext(
+		ctx,
+		"UPDATE users SET verified_at = ? WHERE id = ? AND email = ? AND verified_at IS NULL;",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at.UTC(), id, email)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// UpdatePassword replaces the password hash of the user.
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id int64, hashedPassword string) error {
+	const op = "storage.sqlite.UpdatePassword"
+
+	stmt, err := ur.db.PreparexContext(ctx, "UPDATE users SET password = ? WHERE id = ?;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, hashedPassword, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// UpdateUser saves the email, name, locale and verification time of the user.
+func (ur *UserRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	const op = "storage.sqlite.UpdateUser"
+
+	stmt, err := ur.db.PreparexContext(
+		ctx,
+		"UPDATE users SET email = ?, name = ?, locale = ?, verified_at = ? WHERE id = ?;",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var verifiedAt *time.Time
+	if user.VerifiedAt != nil {
+		at := user.VerifiedAt.UTC()
+		verifiedAt = &at
+	}
+
+	res, err := stmt.ExecContext(ctx, user.Email, user.Name, user.Locale, verifiedAt, user.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserAlreadyExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// DeleteUser deletes the user. Applications, phases and tokens of the user
+// are deleted by the database cascade.
+func (ur *UserRepository) DeleteUser(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteUser"
+
+	stmt, err := ur.db.PreparexContext(ctx, "DELETE FROM users WHERE id = ?;")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(op, res, storage.ErrUserNotFound)
+}
+
+// user returns the single user matching the where condition.
+func (ur *UserRepository) user(ctx context.Context, where string, arg any) (models.User, error) {
+	stmt, err := ur.db.PreparexContext(ctx, "SELECT "+userColumns+" FROM users WHERE "+where+";")
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user dbUser
+	err = stmt.GetContext(ctx, &user, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, storage.ErrUserNotFound
+		}
+
+		return models.User{}, err
+	}
+
+	return models.User{
+		ID:             user.ID,
+		HashedPassword: user.Password,
+		Email:          user.Email,
+		Name:           user.Name,
+		Locale:         user.Locale,
+		VerifiedAt:     user.VerifiedAt,
+	}, nil
+}
+
+const userColumns = "id, password, email, COALESCE(name, '') AS name, locale, verified_at"
+
+type dbUser struct {
+	ID         int64      `db:"id"`
+	Password   string     `db:"password"`
+	Email      string     `db:"email"`
+	Name       string     `db:"name"`
+	Locale     string     `db:"locale"`
+	VerifiedAt *time.Time `db:"verified_at"`
+}
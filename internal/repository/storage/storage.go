@@ -6,4 +6,17 @@ var (
 	ErrTokenAlreadyBlacklisted = errors.New("token already blacklisted")
 	ErrUserAlreadyExists       = errors.New("user already exists")
 	ErrUserNotFound            = errors.New("user not found")
+	ErrApplicationNotFound     = errors.New("application not found")
+	ErrPhaseNotFound           = errors.New("application phase not found")
+	ErrInvalidCursor           = errors.New("invalid cursor")
+	ErrResetTokenNotFound      = errors.New("password reset token not found")
+	ErrTOTPNotFound            = errors.New("totp not found")
+	ErrTOTPAlreadyEnabled      = errors.New("totp already enabled")
+	ErrTOTPStepUsed            = errors.New("totp step already used")
+	ErrRecoveryCodeNotFound    = errors.New("recovery code not found")
+	ErrAPIKeyNotFound          = errors.New("api key not found")
+	ErrAPIKeyAlreadyExists     = errors.New("api key already exists")
+	ErrIdentityNotFound        = errors.New("identity not found")
+	ErrIdentityAlreadyExists   = errors.New("identity already exists")
+	ErrSessionNotFound         = errors.New("session not found")
 )
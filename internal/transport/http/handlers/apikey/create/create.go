@@ -0,0 +1,114 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type request struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,dive,oneof=applications:read applications:write"`
+}
+
+type response struct {
+	resp.Response
+	APIKey models.APIKey `json:"api_key"`
+	// Key is the secret key. It is shown only once.
+	Key string `json:"key"`
+}
+
+type apiKeyCreator interface {
+	CreateAPIKey(ctx context.Context, userID int64, name string, scopes []string) (models.APIKey, string, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, apiKeyCreator apiKeyCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.apikey.create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		key, secret, err := apiKeyCreator.CreateAPIKey(ctx, userID, req.Name, req.Scopes)
+		if err != nil {
+			if errors.Is(err, usecase.ErrAPIKeyAlreadyExists) {
+				msg := "api key with this name already exists"
+				log.Info(msg)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidScope) {
+				msg := "at least one valid scope is required"
+				log.Info(msg, sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			msg := "failed to create api key"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		log.Info("api key created", slog.Int64("id", key.ID))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, response{
+			Response: resp.OK(),
+			APIKey:   key,
+			Key:      secret,
+		})
+	}
+}
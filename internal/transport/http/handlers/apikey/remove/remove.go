@@ -0,0 +1,77 @@
+package remove
+
+import (
+	"context"
+	"errors"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type apiKeyRevoker interface {
+	RevokeAPIKey(ctx context.Context, userID, id int64) error
+}
+
+func New(ctx context.Context, log *slog.Logger, apiKeyRevoker apiKeyRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.apikey.remove"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			msg := "invalid api key id"
+			log.Info(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		err = apiKeyRevoker.RevokeAPIKey(ctx, userID, id)
+		if err != nil {
+			if errors.Is(err, usecase.ErrAPIKeyNotFound) {
+				msg := "api key not found"
+				log.Info(msg)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			msg := "failed to revoke api key"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		log.Info("api key revoked", slog.Int64("id", id))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
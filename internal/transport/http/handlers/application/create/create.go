@@ -0,0 +1,106 @@
+package create
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type request struct {
+	CompanyName    string `json:"company_name" validate:"required"`
+	Position       string `json:"position" validate:"required"`
+	Url            string `json:"url,omitempty" validate:"omitempty,url"`
+	JobDescription string `json:"job_description,omitempty"`
+	Contacts       string `json:"contacts,omitempty"`
+	Cv             string `json:"cv,omitempty"`
+	CoverLetter    string `json:"cover_letter,omitempty"`
+	OfferedSalary  int    `json:"offered_salary,omitempty" validate:"gte=0"`
+}
+
+type response struct {
+	resp.Response
+	Id int64 `json:"id,omitempty"`
+}
+
+type applicationCreator interface {
+	CreateApplication(ctx context.Context, ownerID int64, app models.Application) (int64, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, applicationCreator applicationCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.application.create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		ownerID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		id, err := applicationCreator.CreateApplication(ctx, ownerID, models.Application{
+			CompanyName:    req.CompanyName,
+			Position:       req.Position,
+			Url:            req.Url,
+			JobDescription: req.JobDescription,
+			Contacts:       req.Contacts,
+			Cv:             req.Cv,
+			CoverLetter:    req.CoverLetter,
+			OfferedSalary:  req.OfferedSalary,
+		})
+		if err != nil {
+			msg := "failed to create application"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		log.Info("new application created", slog.Int64("id", id))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, response{
+			Response: resp.OK(),
+			Id:       id,
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type response struct {
+	resp.Response
+	Application models.Application `json:"application"`
+}
+
+type applicationProvider interface {
+	Application(ctx context.Context, ownerID, id int64) (models.Application, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, applicationProvider applicationProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.application.get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		ownerID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			msg := "invalid application id"
+			log.Info(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		app, err := applicationProvider.Application(ctx, ownerID, id)
+		if err != nil {
+			if errors.Is(err, usecase.ErrApplicationNotFound) {
+				msg := "application not found"
+				log.Info(msg)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			msg := "failed to get application"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response:    resp.OK(),
+			Application: app,
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// request holds the query parameters of the listing. Sort is a comma separated
+// list of fields, a leading "-" sorts the field in descending order.
+// Times are RFC 3339, "from" bounds are inclusive and "to" bounds are exclusive.
+type request struct {
+	CompanyName  string
+	Position     string
+	SalaryMin    *int `validate:"omitempty,gte=0"`
+	SalaryMax    *int `validate:"omitempty,gte=0"`
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	ModifiedFrom *time.Time
+	ModifiedTo   *time.Time
+	Phase        string   `validate:"omitempty,oneof=applied screening interview onsite offer accepted rejected withdrawn"`
+	Sort         []string `validate:"unique,dive,oneof=company_name position offered_salary created last_modified"`
+	Limit        int      `validate:"omitempty,gte=1,lte=100"`
+	Cursor       string
+
+	desc []bool
+}
+
+type response struct {
+	resp.Paginated
+	Applications []models.Application `json:"applications"`
+}
+
+type applicationsProvider interface {
+	Applications(ctx context.Context, ownerID int64, q models.ApplicationQuery) (models.ApplicationPage, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, applicationsProvider applicationsProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.application.list"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		ownerID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		req, err := parseRequest(r.URL.Query())
+		if err != nil {
+			log.Info("failed to parse query", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		page, err := applicationsProvider.Applications(ctx, ownerID, req.query())
+		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidCursor) {
+				msg := "invalid cursor"
+				log.Info(msg)
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+
+			msg := "failed to list applications"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Paginated:    resp.OKPage(page.NextCursor),
+			Applications: page.Applications,
+		})
+	}
+}
+
+func parseRequest(values url.Values) (request, error) {
+	req := request{
+		CompanyName: values.Get("company_name"),
+		Position:    values.Get("position"),
+		Phase:       values.Get("phase"),
+		Cursor:      values.Get("cursor"),
+	}
+
+	var err error
+
+	ints := map[string]**int{
+		"salary_min": &req.SalaryMin,
+		"salary_max": &req.SalaryMax,
+	}
+	for name, dst := range ints {
+		if *dst, err = parseInt(values, name); err != nil {
+			return request{}, err
+		}
+	}
+
+	times := map[string]**time.Time{
+		"created_from":  &req.CreatedFrom,
+		"created_to":    &req.CreatedTo,
+		"modified_from": &req.ModifiedFrom,
+		"modified_to":   &req.ModifiedTo,
+	}
+	for name, dst := range times {
+		if *dst, err = parseTime(values, name); err != nil {
+			return request{}, err
+		}
+	}
+
+	if limit, err := parseInt(values, "limit"); err != nil {
+		return request{}, err
+	} else if limit != nil {
+		req.Limit = *limit
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		for _, field := range strings.Split(sort, ",") {
+			field, desc := strings.CutPrefix(strings.TrimSpace(field), "-")
+
+			req.Sort = append(req.Sort, field)
+			req.desc = append(req.desc, desc)
+		}
+	}
+
+	return req, nil
+}
+
+func (req *request) query() models.ApplicationQuery {
+	q := models.ApplicationQuery{
+		Filter: models.ApplicationFilter{
+			CompanyName:  req.CompanyName,
+			Position:     req.Position,
+			MinSalary:    req.SalaryMin,
+			MaxSalary:    req.SalaryMax,
+			CreatedFrom:  req.CreatedFrom,
+			CreatedTo:    req.CreatedTo,
+			ModifiedFrom: req.ModifiedFrom,
+			ModifiedTo:   req.ModifiedTo,
+			Phase:        req.Phase,
+		},
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+	}
+
+	for i, field := range req.Sort {
+		q.Sort = append(q.Sort, models.SortOrder{Field: field, Desc: req.desc[i]})
+	}
+
+	return q
+}
+
+func parseInt(values url.Values, name string) (*int, error) {
+	s := values.Get(name)
+	if s == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter %s must be an integer", name)
+	}
+
+	return &n, nil
+}
+
+func parseTime(values url.Values, name string) (*time.Time, error) {
+	s := values.Get(name)
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter %s must be an RFC 3339 time", name)
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,95 @@
+package search
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type request struct {
+	Query string `validate:"required,max=200"`
+	Limit int    `validate:"omitempty,gte=1,lte=100"`
+}
+
+type response struct {
+	resp.Response
+	Results []models.ApplicationSearchHit `json:"results"`
+}
+
+type applicationsSearcher interface {
+	SearchApplications(ctx context.Context, ownerID int64, q string, limit int) ([]models.ApplicationSearchHit, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, applicationsSearcher applicationsSearcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.application.search"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		ownerID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		req := request{Query: r.URL.Query().Get("q")}
+
+		if limit := r.URL.Query().Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil {
+				msg := "query parameter limit must be an integer"
+				log.Info(msg, sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+
+			req.Limit = n
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		hits, err := applicationsSearcher.SearchApplications(ctx, ownerID, req.Query, req.Limit)
+		if err != nil {
+			msg := "failed to search applications"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response: resp.OK(),
+			Results:  hits,
+		})
+	}
+}
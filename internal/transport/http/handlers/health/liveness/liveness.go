@@ -0,0 +1,15 @@
+package liveness
+
+import (
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+// New reports that the process is alive. It never touches dependencies.
+func New() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
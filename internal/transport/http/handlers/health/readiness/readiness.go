@@ -0,0 +1,50 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const checkTimeout = 3 * time.Second
+
+type readinessChecker interface {
+	Ready(ctx context.Context) error
+}
+
+func New(log *slog.Logger, readinessChecker readinessChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.health.readiness"
+
+		log := log.With(slog.String("op", op))
+
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		if err := readinessChecker.Ready(ctx); err != nil {
+			msg := "not ready"
+			switch {
+			case errors.Is(err, usecase.ErrDatabaseUnavailable):
+				msg = "database unavailable"
+			case errors.Is(err, usecase.ErrMigrationsPending):
+				msg = "migrations pending"
+			}
+
+			log.Info(msg, sl.Err(err))
+
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
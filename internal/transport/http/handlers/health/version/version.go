@@ -0,0 +1,23 @@
+package version
+
+import (
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/buildinfo"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+type response struct {
+	resp.Response
+	buildinfo.Info
+}
+
+func New(info buildinfo.Info) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response: resp.OK(),
+			Info:     info,
+		})
+	}
+}
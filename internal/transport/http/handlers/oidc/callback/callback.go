@@ -0,0 +1,163 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/api/clientip"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+// stateCookie is set by the start handler.
+const stateCookie = "oidc_state"
+
+// response is the same as the one of the password login.
+type response struct {
+	resp.Response
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+type oidcFinisher interface {
+	FinishOIDC(ctx context.Context, provider, stateToken, state, code string, client models.Client) (models.Tokens, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, oidcFinisher oidcFinisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.oidc.callback"
+
+		provider := chi.URLParam(r, "provider")
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			slog.String("provider", provider),
+		)
+
+		// The state is single use whatever the outcome.
+		http.SetCookie(w, &http.Cookie{
+			Name:     stateCookie,
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		q := r.URL.Query()
+
+		if providerErr := q.Get("error"); providerErr != "" {
+			msg := "login with the identity provider failed"
+			log.Info(msg, slog.String("error", providerErr), slog.String("description", q.Get("error_description")))
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		cookie, err := r.Cookie(stateCookie)
+		if err != nil || q.Get("code") == "" || q.Get("state") == "" {
+			msg := "invalid or expired login state"
+			log.Info(msg)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		tokens, err := oidcFinisher.FinishOIDC(
+			ctx,
+			provider,
+			cookie.Value,
+			q.Get("state"),
+			q.Get("code"),
+			models.Client{UserAgent: r.UserAgent(), IP: clientip.FromRequest(r)},
+		)
+		if err != nil {
+			if errors.Is(err, usecase.ErrProviderNotFound) {
+				msg := "identity provider not found"
+				log.Info(msg)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidToken) {
+				msg := "invalid or expired login state"
+				log.Info(msg)
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrExternalLoginFailed) {
+				msg := "login with the identity provider failed"
+				log.Info(msg)
+
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrEmailNotVerified) {
+				msg := "email is not verified"
+				log.Info(msg)
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrUserAlreadyExists) {
+				msg := "an account with this email exists, verify it and log in with the password first"
+				log.Info(msg)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			log.Error("failed to login user", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("something went wrong"))
+
+			return
+		}
+
+		if tokens.MFA != "" {
+			log.Info("second factor required")
+
+			render.Status(r, http.StatusOK)
+			render.JSON(w, r, response{
+				Response:    resp.OK(),
+				MFARequired: true,
+				MFAToken:    tokens.MFA,
+			})
+
+			return
+		}
+
+		log.Info("user logged in")
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response:     resp.OK(),
+			AccessToken:  tokens.Access,
+			RefreshToken: tokens.Refresh,
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package start
+
+import (
+	"context"
+	"errors"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+// stateCookie keeps the state token until the callback. It is read by the callback handler.
+const stateCookie = "oidc_state"
+
+type oidcStarter interface {
+	StartOIDC(ctx context.Context, provider string) (string, string, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, oidcStarter oidcStarter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.oidc.start"
+
+		provider := chi.URLParam(r, "provider")
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			slog.String("provider", provider),
+		)
+
+		authURL, stateToken, err := oidcStarter.StartOIDC(ctx, provider)
+		if err != nil {
+			if errors.Is(err, usecase.ErrProviderNotFound) {
+				msg := "identity provider not found"
+				log.Info(msg)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			msg := "failed to start login with the identity provider"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadGateway)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		// Lax lets the cookie through on the top-level redirect back from the provider.
+		http.SetCookie(w, &http.Cookie{
+			Name:     stateCookie,
+			Value:    stateToken,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
@@ -0,0 +1,122 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type request struct {
+	Name  string    `json:"name" validate:"required,oneof=applied screening interview onsite offer accepted rejected withdrawn"`
+	Date  time.Time `json:"date" validate:"required"`
+	Notes string    `json:"notes,omitempty"`
+}
+
+type response struct {
+	resp.Response
+	Id int64 `json:"id,omitempty"`
+}
+
+type phaseCreator interface {
+	AddPhase(ctx context.Context, ownerID int64, phase models.ApplicationPhase) (int64, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, phaseCreator phaseCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.phase.create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		ownerID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		applicationID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			msg := "invalid application id"
+			log.Info(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		id, err := phaseCreator.AddPhase(ctx, ownerID, models.ApplicationPhase{
+			Name:          req.Name,
+			Date:          req.Date,
+			Notes:         req.Notes,
+			ApplicationID: applicationID,
+		})
+		if err != nil {
+			if errors.Is(err, usecase.ErrApplicationNotFound) {
+				msg := "application not found"
+				log.Info(msg)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			msg := "failed to add phase"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		log.Info("new phase added", slog.Int64("id", id))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, response{
+			Response: resp.OK(),
+			Id:       id,
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package changepassword
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/api/clientip"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type request struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// response carries a new token pair, the tokens the request was made with are revoked.
+type response struct {
+	resp.Response
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type passwordChanger interface {
+	ChangePassword(
+		ctx context.Context,
+		userID int64,
+		currentPassword, newPassword string,
+		client models.Client,
+	) (models.Tokens, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, passwordChanger passwordChanger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.profile.changepassword"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		tokens, err := passwordChanger.ChangePassword(
+			ctx,
+			userID,
+			req.CurrentPassword,
+			req.NewPassword,
+			models.Client{UserAgent: r.UserAgent(), IP: clientip.FromRequest(r)},
+		)
+		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidCredentials) {
+				log.Info("invalid current password")
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("invalid current password"))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrUserNotFound) {
+				msg := "user not found"
+				log.Info(msg)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			log.Error("failed to change password", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("something went wrong"))
+
+			return
+		}
+
+		log.Info("password changed")
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response:     resp.OK(),
+			AccessToken:  tokens.Access,
+			RefreshToken: tokens.Refresh,
+		})
+	}
+}
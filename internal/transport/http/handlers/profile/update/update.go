@@ -0,0 +1,115 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+// request is a partial update, omitted fields are left as they are.
+type request struct {
+	Email  *string `json:"email" validate:"omitempty,email"`
+	Name   *string `json:"name"`
+	Locale *string `json:"locale" validate:"omitempty,bcp47_language_tag"`
+}
+
+type response struct {
+	resp.Response
+	User models.User `json:"user"`
+}
+
+type profileUpdater interface {
+	UpdateProfile(ctx context.Context, userID int64, upd models.UserUpdate) (models.User, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, profileUpdater profileUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.profile.update"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		user, err := profileUpdater.UpdateProfile(ctx, userID, models.UserUpdate{
+			Email:  req.Email,
+			Name:   req.Name,
+			Locale: req.Locale,
+		})
+		if err != nil {
+			if errors.Is(err, usecase.ErrUserAlreadyExists) {
+				msg := "email is already taken"
+				log.Info(msg)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrUserNotFound) {
+				msg := "user not found"
+				log.Info(msg)
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			msg := "failed to update profile"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response: resp.OK(),
+			User:     user,
+		})
+	}
+}
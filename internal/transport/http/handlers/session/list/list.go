@@ -0,0 +1,60 @@
+package list
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type response struct {
+	resp.Response
+	Sessions []models.Session `json:"sessions"`
+}
+
+type sessionsProvider interface {
+	Sessions(ctx context.Context, claims models.TokenClaims) ([]models.Session, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, sessionsProvider sessionsProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.session.list"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		claims, ok := auth.ClaimsFromContext(r.Context())
+		if !ok {
+			log.Error("token claims are missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		sessions, err := sessionsProvider.Sessions(ctx, claims)
+		if err != nil {
+			msg := "failed to get sessions"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response: resp.OK(),
+			Sessions: sessions,
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package confirm
+
+import (
+	"context"
+	"errors"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type request struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// response carries the recovery codes. They are shown once.
+type response struct {
+	resp.Response
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type twoFactorConfirmer interface {
+	ConfirmTwoFactor(ctx context.Context, userID int64, code string) ([]string, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, twoFactorConfirmer twoFactorConfirmer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.twofactor.confirm"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		codes, err := twoFactorConfirmer.ConfirmTwoFactor(ctx, userID, req.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrInvalidCode):
+				msg := "invalid code"
+				log.Info(msg)
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error(msg))
+			case errors.Is(err, usecase.ErrTwoFactorNotEnabled):
+				msg := "two-factor authentication is not set up"
+				log.Info(msg)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error(msg))
+			case errors.Is(err, usecase.ErrTwoFactorEnabled):
+				msg := "two-factor authentication is already enabled"
+				log.Info(msg)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error(msg))
+			default:
+				msg := "failed to confirm two-factor authentication"
+				log.Error(msg, sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error(msg))
+			}
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response:      resp.OK(),
+			RecoveryCodes: codes,
+		})
+	}
+}
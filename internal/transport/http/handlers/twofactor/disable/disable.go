@@ -0,0 +1,97 @@
+package disable
+
+import (
+	"context"
+	"errors"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+// request proves the user still has the second factor.
+// Code is a code of the authenticator app or a recovery code.
+type request struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type twoFactorDisabler interface {
+	DisableTwoFactor(ctx context.Context, userID int64, code string) error
+}
+
+func New(ctx context.Context, log *slog.Logger, twoFactorDisabler twoFactorDisabler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.twofactor.disable"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		if err := twoFactorDisabler.DisableTwoFactor(ctx, userID, req.Code); err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrInvalidCode):
+				msg := "invalid code"
+				log.Info(msg)
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error(msg))
+			case errors.Is(err, usecase.ErrTwoFactorNotEnabled):
+				msg := "two-factor authentication is not enabled"
+				log.Info(msg)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error(msg))
+			default:
+				msg := "failed to disable two-factor authentication"
+				log.Error(msg, sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error(msg))
+			}
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
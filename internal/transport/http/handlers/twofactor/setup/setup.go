@@ -0,0 +1,80 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type response struct {
+	resp.Response
+	models.TOTPSetup
+}
+
+type twoFactorSetuper interface {
+	SetupTwoFactor(ctx context.Context, userID int64) (models.TOTPSetup, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, twoFactorSetuper twoFactorSetuper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.twofactor.setup"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			log.Error("user id is missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		setup, err := twoFactorSetuper.SetupTwoFactor(ctx, userID)
+		if err != nil {
+			if errors.Is(err, usecase.ErrTwoFactorEnabled) {
+				msg := "two-factor authentication is already enabled"
+				log.Info(msg)
+
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrTwoFactorDisabled) {
+				msg := "two-factor authentication is disabled"
+				log.Info(msg)
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+			msg := "failed to set up two-factor authentication"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response:  resp.OK(),
+			TOTPSetup: setup,
+		})
+	}
+}
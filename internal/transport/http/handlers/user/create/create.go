@@ -17,6 +17,7 @@ type request struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
 	Name     string `json:"name,omitempty"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag"`
 }
 
 type response struct {
@@ -25,14 +26,14 @@ type response struct {
 }
 
 type userCreator interface {
-	CreateUser(ctx context.Context, email, password, name string) (int64, error)
+	CreateUser(ctx context.Context, email, password, name, locale string) (int64, error)
 }
 
 func New(ctx context.Context, log *slog.Logger, userCreator userCreator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http.handlers.user.create"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -60,7 +61,7 @@ func New(ctx context.Context, log *slog.Logger, userCreator userCreator) http.Ha
 			return
 		}
 
-		id, err := userCreator.CreateUser(ctx, req.Email, req.Password, req.Name)
+		id, err := userCreator.CreateUser(ctx, req.Email, req.Password, req.Name, req.Locale)
 		if err != nil {
 			if errors.Is(err, usecase.ErrUserAlreadyExists) {
 				msg := "user with this email already exists"
@@ -0,0 +1,63 @@
+package forgotpassword
+
+import (
+	"context"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type request struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type passwordResetRequester interface {
+	ForgotPassword(ctx context.Context, email string) error
+}
+
+// New responds with 200 to every valid request whether the account exists or not
+// and whatever happens to it, so the endpoint can't be used to find registered emails.
+func New(ctx context.Context, log *slog.Logger, passwordResetRequester passwordResetRequester) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.user.forgotpassword"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		if err := passwordResetRequester.ForgotPassword(ctx, req.Email); err != nil {
+			log.Error("failed to request password reset", sl.Err(err))
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
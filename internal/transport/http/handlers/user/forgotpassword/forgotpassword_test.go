@@ -0,0 +1,63 @@
+package forgotpassword_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/forgotpassword"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingMailSender struct{}
+
+func (failingMailSender) SendTemplate(ctx context.Context, to, locale, name string, data any) error {
+	return errors.New("smtp is down")
+}
+
+type noopUnlocker struct{}
+
+func (noopUnlocker) UnlockAccount(ctx context.Context, email string) error {
+	return nil
+}
+
+func TestNew_SendFailure(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := memory.NewDB()
+
+	_, err := memory.NewUserRepository(db).SaveUser(ctx, &models.User{Email: "jane@example.com", HashedPassword: "hash"})
+	require.NoError(t, err)
+
+	h := forgotpassword.New(ctx, log, usecase.NewPasswordResetUsecase(
+		password_hasher.NewBcryptPasswordHasher(),
+		memory.NewUserRepository(db),
+		memory.NewPasswordResetRepository(db),
+		memory.NewTokenRepository(db),
+		noopUnlocker{},
+		failingMailSender{},
+		usecase.PasswordResetOptions{TokenTTL: time.Hour},
+		log,
+	))
+
+	// Known and unknown emails get the same response even when the email can't be sent.
+	for _, email := range []string{"jane@example.com", "john@example.com"} {
+		r := httptest.NewRequest(http.MethodPost, "/forgot-password", strings.NewReader(`{"email":"`+email+`"}`))
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, r)
+
+		assert.Equal(t, http.StatusOK, rec.Code, email)
+		assert.JSONEq(t, `{"status":"OK"}`, rec.Body.String(), email)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/api/clientip"
 	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
 	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
 	"github.com/diproducts/application-tracker-go/internal/usecase"
@@ -11,7 +12,9 @@ import (
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type request struct {
@@ -19,21 +22,25 @@ type request struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// response carries either the token pair or, when two-factor authentication
+// is enabled, the mfa token to exchange for it at /auth/2fa.
 type response struct {
 	resp.Response
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
 }
 
 type loginProvider interface {
-	Login(ctx context.Context, email, password string) (models.Tokens, error)
+	Login(ctx context.Context, email, password string, client models.Client) (models.Tokens, error)
 }
 
 func New(ctx context.Context, log *slog.Logger, loginProvider loginProvider) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http.handlers.user.login"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -61,8 +68,23 @@ func New(ctx context.Context, log *slog.Logger, loginProvider loginProvider) htt
 			return
 		}
 
-		tokens, err := loginProvider.Login(ctx, req.Email, req.Password)
+		tokens, err := loginProvider.Login(
+			ctx,
+			req.Email,
+			req.Password,
+			models.Client{UserAgent: r.UserAgent(), IP: clientip.FromRequest(r)},
+		)
 		if err != nil {
+			var lockoutErr *usecase.LockoutError
+			if errors.As(err, &lockoutErr) {
+				log.Info("login locked out")
+
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockoutErr.RetryAfter.Seconds()))))
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, resp.Error("too many failed login attempts, try again later"))
+
+				return
+			}
 			if errors.Is(err, usecase.ErrInvalidCredentials) {
 				log.Info("invalid credentials")
 
@@ -71,6 +93,14 @@ func New(ctx context.Context, log *slog.Logger, loginProvider loginProvider) htt
 
 				return
 			}
+			if errors.Is(err, usecase.ErrEmailNotVerified) {
+				log.Info("email is not verified")
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("email is not verified"))
+
+				return
+			}
 			log.Error("failed to login user", sl.Err(err))
 
 			render.Status(r, http.StatusInternalServerError)
@@ -79,6 +109,19 @@ func New(ctx context.Context, log *slog.Logger, loginProvider loginProvider) htt
 			return
 		}
 
+		if tokens.MFA != "" {
+			log.Info("second factor required")
+
+			render.Status(r, http.StatusOK)
+			render.JSON(w, r, response{
+				Response:    resp.OK(),
+				MFARequired: true,
+				MFAToken:    tokens.MFA,
+			})
+
+			return
+		}
+
 		log.Info("user logged in")
 
 		render.Status(r, http.StatusOK)
@@ -0,0 +1,52 @@
+package logoutall
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type allLogouter interface {
+	LogoutAll(ctx context.Context, claims models.TokenClaims) error
+}
+
+func New(ctx context.Context, log *slog.Logger, allLogouter allLogouter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.user.logoutall"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		claims, ok := auth.ClaimsFromContext(r.Context())
+		if !ok {
+			log.Error("token claims are missing in request context")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("unauthorized"))
+
+			return
+		}
+
+		if err := allLogouter.LogoutAll(ctx, claims); err != nil {
+			log.Error("failed to logout user", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("something went wrong"))
+
+			return
+		}
+
+		log.Info("user logged out from all devices")
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
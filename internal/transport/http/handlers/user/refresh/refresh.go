@@ -0,0 +1,91 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/api/clientip"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type request struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type response struct {
+	resp.Response
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type tokenRefresher interface {
+	Refresh(ctx context.Context, refreshToken string, client models.Client) (models.Tokens, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, tokenRefresher tokenRefresher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.user.refresh"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		tokens, err := tokenRefresher.Refresh(ctx, req.RefreshToken, models.Client{UserAgent: r.UserAgent(), IP: clientip.FromRequest(r)})
+		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidToken) || errors.Is(err, usecase.ErrTokenReused) {
+				log.Info("refresh rejected", sl.Err(err))
+
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("invalid refresh token"))
+
+				return
+			}
+			log.Error("failed to refresh tokens", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("something went wrong"))
+
+			return
+		}
+
+		log.Info("tokens refreshed")
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response:     resp.OK(),
+			AccessToken:  tokens.Access,
+			RefreshToken: tokens.Refresh,
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/api/clientip"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// request finishes a login with two-factor authentication.
+// Code is a code of the authenticator app or a recovery code.
+type request struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type response struct {
+	resp.Response
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type twoFactorLoginProvider interface {
+	LoginTwoFactor(ctx context.Context, mfaToken, code string, client models.Client) (models.Tokens, error)
+}
+
+func New(ctx context.Context, log *slog.Logger, twoFactorLoginProvider twoFactorLoginProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http.handlers.user.twofactor"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			msg := "failed to decode request"
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(msg))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Info("invalid request", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		tokens, err := twoFactorLoginProvider.LoginTwoFactor(
+			ctx,
+			req.MFAToken,
+			req.Code,
+			models.Client{UserAgent: r.UserAgent(), IP: clientip.FromRequest(r)},
+		)
+		if err != nil {
+			var lockoutErr *usecase.LockoutError
+			if errors.As(err, &lockoutErr) {
+				log.Info("second factor locked out")
+
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockoutErr.RetryAfter.Seconds()))))
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, resp.Error("too many invalid codes, try again later"))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidToken) {
+				log.Info("invalid mfa token")
+
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("invalid or expired mfa token"))
+
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidCode) {
+				log.Info("invalid code")
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("invalid code"))
+
+				return
+			}
+			log.Error("failed to login user", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("something went wrong"))
+
+			return
+		}
+
+		log.Info("user logged in")
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, response{
+			Response:     resp.OK(),
+			AccessToken:  tokens.Access,
+			RefreshToken: tokens.Refresh,
+		})
+	}
+}
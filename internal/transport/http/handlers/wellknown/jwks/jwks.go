@@ -0,0 +1,26 @@
+package jwks
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+// cacheControl is short enough for verifiers to pick up keys added to the
+// rotation schedule well before they start signing.
+const cacheControl = "public, max-age=300"
+
+type keySet interface {
+	JWKS() tokenutil.JWKS
+}
+
+// New serves the public keys access tokens are verified with. The body is a bare
+// JSON Web Key Set rather than the usual API response, as verifiers expect.
+func New(keySet keySet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", cacheControl)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, keySet.JWKS())
+	}
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type apiKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (models.TokenClaims, error)
+}
+
+// APIKeyHeader is the header API keys are passed in.
+const APIKeyHeader = "X-API-Key"
+
+// New accepts either an access token in the Authorization header or an API key in the X-API-Key header.
+// Both resolve to the same user, requests authenticated with a key are limited by RequireScope.
+func New(log *slog.Logger, authenticator authenticator, apiKeyAuthenticator apiKeyAuthenticator) func(http.Handler) http.Handler {
+	jwt := NewJWTMiddleware(log, authenticator)
+
+	return func(next http.Handler) http.Handler {
+		withJWT := jwt(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "http.middleware.auth.New"
+
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				withJWT.ServeHTTP(w, r)
+
+				return
+			}
+
+			log := log.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			if r.Header.Get("Authorization") != "" {
+				msg := "Use either an access token or an API key"
+				log.Info(msg)
+
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+
+			claims, err := apiKeyAuthenticator.AuthenticateAPIKey(r.Context(), key)
+			serveAuthenticated(w, r, next, log, claims, err, "API key")
+		})
+	}
+}
+
+// RequireScope rejects requests authenticated with an API key that wasn't granted the scope.
+// It must be used after New.
+func RequireScope(log *slog.Logger, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "http.middleware.auth.RequireScope"
+
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok || !claims.HasScope(scope) {
+				log := log.With(
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+				)
+
+				msg := "API key is missing the " + scope + " scope"
+				log.Info(msg, slog.Int64("api_key_id", claims.APIKeyID))
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error(msg))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
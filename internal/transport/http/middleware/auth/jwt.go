@@ -3,8 +3,11 @@ package auth
 import (
 	"context"
 	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
 	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
-	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/requestlog"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"log/slog"
@@ -12,16 +15,25 @@ import (
 	"strings"
 )
 
-type tokenManager interface {
-	ExtractUserIDFromAccessToken(tokenStr string) (int64, error)
+type ctxKey string
+
+const (
+	userIDKey ctxKey = "userID"
+	claimsKey ctxKey = "claims"
+)
+
+type authenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (models.TokenClaims, error)
 }
 
-func NewJWTMiddleware(log *slog.Logger, tm tokenManager) func(http.Handler) http.Handler {
+// NewJWTMiddleware accepts only access tokens. It guards the routes API keys must not reach,
+// such as account management.
+func NewJWTMiddleware(log *slog.Logger, authenticator authenticator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			const op = "http.middleware.auth.NewJWTMiddleware"
 
-			log = log.With(
+			log := log.With(
 				slog.String("op", op),
 				slog.String("request_id", middleware.GetReqID(r.Context())),
 			)
@@ -39,29 +51,57 @@ func NewJWTMiddleware(log *slog.Logger, tm tokenManager) func(http.Handler) http
 
 			tokenStr = strings.TrimPrefix(tokenStr, "Bearer ")
 
-			userID, err := tm.ExtractUserIDFromAccessToken(tokenStr)
-			if err != nil {
-				if errors.Is(err, tokenutil.ErrInvalidToken) {
-					msg := "Invalid access token"
+			claims, err := authenticator.Authenticate(r.Context(), tokenStr)
+			serveAuthenticated(w, r, next, log, claims, err, "access token")
+		})
+	}
+}
 
-					log.Info(msg)
+// serveAuthenticated passes the request with the claims to next if the credential was valid.
+func serveAuthenticated(
+	w http.ResponseWriter,
+	r *http.Request,
+	next http.Handler,
+	log *slog.Logger,
+	claims models.TokenClaims,
+	err error,
+	credential string,
+) {
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			msg := "Invalid " + credential
 
-					render.Status(r, http.StatusUnauthorized)
-					render.JSON(w, r, resp.Error(msg))
+			log.Info(msg)
 
-					return
-				}
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(msg))
 
-				log.Error("failed to validate access token")
+			return
+		}
 
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("internal server error"))
+		log.Error("failed to validate "+credential, sl.Err(err))
 
-				return
-			}
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("internal server error"))
 
-			ctx := context.WithValue(r.Context(), "userID", userID)
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
+		return
 	}
+
+	requestlog.SetUserID(r.Context(), claims.UserID)
+
+	ctx := context.WithValue(r.Context(), userIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, claimsKey, claims)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UserIDFromContext returns the id of the authenticated user stored by the auth middlewares.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey).(int64)
+	return userID, ok
+}
+
+// ClaimsFromContext returns the claims of the access token or API key the request was authenticated with.
+func ClaimsFromContext(ctx context.Context) (models.TokenClaims, bool) {
+	claims, ok := ctx.Value(claimsKey).(models.TokenClaims)
+	return claims, ok
 }
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/api/clientip"
+	resp "github.com/diproducts/application-tracker-go/internal/lib/api/response"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type limiter interface {
+	Allow(ctx context.Context, client, method, path string) (models.RateLimitResult, error)
+}
+
+// New limits the requests of every client. Authenticated users are told apart by their id,
+// so it has to come after the auth middleware to apply per user, and other clients by address.
+// The limit is reported in the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+// When the store of the limiter fails requests are let through.
+func New(log *slog.Logger, limiter limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "http.middleware.ratelimit.New"
+
+			client := "ip:" + clientip.FromRequest(r)
+			if userID, ok := auth.UserIDFromContext(r.Context()); ok {
+				client = "user:" + strconv.FormatInt(userID, 10)
+			}
+
+			res, err := limiter.Allow(r.Context(), client, r.Method, r.URL.Path)
+			if err != nil {
+				log.Error(
+					"failed to check rate limit",
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					sl.Err(err),
+				)
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if res.Limit > 0 {
+				w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+				w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+				w.Header().Set("RateLimit-Reset", fmt.Sprint(ceilSeconds(res.Reset)))
+			}
+
+			if !res.Allowed {
+				log.Info(
+					"rate limit exceeded",
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					slog.String("client", client),
+				)
+
+				w.Header().Set("Retry-After", fmt.Sprint(ceilSeconds(res.RetryAfter)))
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, resp.Error("rate limit exceeded, try again later"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
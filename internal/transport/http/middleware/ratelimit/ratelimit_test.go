@@ -0,0 +1,83 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type limiterFunc func(client string) (models.RateLimitResult, error)
+
+func (f limiterFunc) Allow(_ context.Context, client, _, _ string) (models.RateLimitResult, error) {
+	return f(client)
+}
+
+func serve(limiter limiterFunc) *httptest.ResponseRecorder {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := ratelimit.New(log, limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/applications", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	return rec
+}
+
+func TestNew_Allowed(t *testing.T) {
+	var gotClient string
+	rec := serve(func(client string) (models.RateLimitResult, error) {
+		gotClient = client
+
+		return models.RateLimitResult{Allowed: true, Limit: 10, Remaining: 9, Reset: 1500 * time.Millisecond}, nil
+	})
+
+	assert.Equal(t, "ip:192.0.2.1", gotClient)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "9", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestNew_Exceeded(t *testing.T) {
+	rec := serve(func(string) (models.RateLimitResult, error) {
+		return models.RateLimitResult{Limit: 10, Reset: time.Minute, RetryAfter: 5500 * time.Millisecond}, nil
+	})
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "6", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), "rate limit exceeded")
+}
+
+func TestNew_Unlimited(t *testing.T) {
+	rec := serve(func(string) (models.RateLimitResult, error) {
+		return models.RateLimitResult{Allowed: true}, nil
+	})
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
+
+func TestNew_StoreFailure(t *testing.T) {
+	rec := serve(func(string) (models.RateLimitResult, error) {
+		return models.RateLimitResult{}, errors.New("store is down")
+	})
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
@@ -0,0 +1,118 @@
+package realip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseTrustedProxies parses the addresses and CIDR ranges of trusted proxies.
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	const op = "http.middleware.realip.ParseTrustedProxies"
+
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			addr, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// New sets RemoteAddr to the address of the client, without a port. The X-Forwarded-For
+// and X-Real-IP headers are only honoured on connections from trusted proxies, any client
+// could send them. X-Forwarded-For is read from the right, the client is the first address
+// that isn't a trusted proxy. Other requests keep the address of the socket peer.
+func New(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	trusted := func(addr netip.Addr) bool {
+		for _, p := range trustedProxies {
+			if p.Contains(addr) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if addr, ok := clientAddr(r, trusted); ok {
+				r.RemoteAddr = addr.String()
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientAddr(r *http.Request, trusted func(netip.Addr) bool) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	peer, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	addr := peer.Unmap()
+	if !trusted(addr) {
+		return addr, true
+	}
+
+	hops := forwardedFor(r.Header)
+	if len(hops) == 0 {
+		if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+			return realIP.Unmap(), true
+		}
+
+		return addr, true
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			break
+		}
+
+		addr = hop.Unmap()
+		if !trusted(addr) {
+			break
+		}
+	}
+
+	return addr, true
+}
+
+// forwardedFor returns the addresses of all X-Forwarded-For headers in order.
+func forwardedFor(h http.Header) []string {
+	var hops []string
+	for _, v := range h.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	return hops
+}
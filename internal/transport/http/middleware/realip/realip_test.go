@@ -0,0 +1,84 @@
+package realip_test
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/realip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	trusted, err := realip.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "untrusted peer",
+			remoteAddr: "203.0.113.7:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-IP": "198.51.100.2"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted peer without headers",
+			remoteAddr: "10.1.2.3:1234",
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "forwarded for",
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "spoofed forwarded for",
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.1, 192.0.2.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "invalid hop",
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, garbage, 192.0.2.1"},
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "real ip",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Real-IP": "2001:db8::1"},
+			want:       "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := realip.New(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), r)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	_, err := realip.ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+
+	_, err = realip.ParseTrustedProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
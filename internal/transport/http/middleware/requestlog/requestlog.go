@@ -0,0 +1,83 @@
+package requestlog
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type ctxKey string
+
+const entryKey ctxKey = "requestlog"
+
+// entry collects what inner middlewares learn about the request.
+type entry struct {
+	userID int64
+}
+
+// New logs one line per request once it is served: the method, the route pattern
+// rather than the path so that ids don't end up in the logs, the status, the latency,
+// the bytes written, the request id and the user, if any. Server errors are logged
+// as errors. It has to come after the RequestID middleware and before the routes.
+func New(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "http.middleware.requestlog.New"
+
+			e := &entry{}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				attrs := []slog.Attr{
+					slog.String("op", op),
+					slog.String("method", r.Method),
+					slog.String("route", routePattern(r)),
+					slog.Int("status", status),
+					slog.Duration("latency", time.Since(start)),
+					slog.Int("bytes", ww.BytesWritten()),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+				}
+				if e.userID != 0 {
+					attrs = append(attrs, slog.Int64("user_id", e.userID))
+				}
+
+				level := slog.LevelInfo
+				if status >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+
+				log.LogAttrs(r.Context(), level, "request served", attrs...)
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), entryKey, e)))
+		})
+	}
+}
+
+// SetUserID records the authenticated user of the request for its log line.
+// It does nothing outside of the middleware.
+func SetUserID(ctx context.Context, userID int64) {
+	if e, ok := ctx.Value(entryKey).(*entry); ok {
+		e.userID = userID
+	}
+}
+
+// routePattern is the pattern of the route that served the request,
+// chi fills it in while routing. Unmatched requests have none.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+
+	return rctx.RoutePattern()
+}
@@ -0,0 +1,81 @@
+package requestlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/requestlog"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRouter(buf *bytes.Buffer) chi.Router {
+	log := slog.New(slog.NewJSONHandler(buf, nil))
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(requestlog.New(log))
+	r.Get("/applications/{id}", func(w http.ResponseWriter, r *http.Request) {
+		requestlog.SetUserID(r.Context(), 42)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+	r.Get("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	return r
+}
+
+func serve(t *testing.T, path string) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	newRouter(&buf).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), "exactly one log line is expected")
+
+	return line
+}
+
+func TestNew(t *testing.T) {
+	line := serve(t, "/applications/123")
+
+	assert.Equal(t, "INFO", line["level"])
+	assert.Equal(t, "request served", line["msg"])
+	assert.Equal(t, http.MethodGet, line["method"])
+	assert.Equal(t, "/applications/{id}", line["route"])
+	assert.EqualValues(t, http.StatusCreated, line["status"])
+	assert.EqualValues(t, 5, line["bytes"])
+	assert.EqualValues(t, 42, line["user_id"])
+	assert.NotEmpty(t, line["request_id"])
+	assert.Contains(t, line, "latency")
+}
+
+func TestNew_ServerError(t *testing.T) {
+	line := serve(t, "/broken")
+
+	assert.Equal(t, "ERROR", line["level"])
+	assert.EqualValues(t, http.StatusInternalServerError, line["status"])
+	assert.NotContains(t, line, "user_id")
+}
+
+func TestNew_NotFound(t *testing.T) {
+	line := serve(t, "/missing")
+
+	assert.EqualValues(t, http.StatusNotFound, line["status"])
+	assert.Empty(t, line["route"])
+}
+
+func TestSetUserID_OutsideMiddleware(t *testing.T) {
+	assert.NotPanics(t, func() {
+		requestlog.SetUserID(httptest.NewRequest(http.MethodGet, "/", nil).Context(), 1)
+	})
+}
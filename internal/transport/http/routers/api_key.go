@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	apikeycreate "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/apikey/create"
+	apikeylist "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/apikey/list"
+	apikeyremove "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/apikey/remove"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+)
+
+type apiKeyManager interface {
+	CreateAPIKey(ctx context.Context, userID int64, name string, scopes []string) (models.APIKey, string, error)
+	APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, id int64) error
+	AuthenticateAPIKey(ctx context.Context, key string) (models.TokenClaims, error)
+}
+
+// NewAPIKeyRoutes is mounted under /me/api-keys. Keys can only be managed with an access token.
+func NewAPIKeyRoutes(ctx context.Context, log *slog.Logger, apiKeyManager apiKeyManager) chi.Router {
+	r := chi.NewRouter()
+	r.Post("/", apikeycreate.New(ctx, log, apiKeyManager))
+	r.Get("/", apikeylist.New(ctx, log, apiKeyManager))
+	r.Delete("/{id}", apikeyremove.New(ctx, log, apiKeyManager))
+	return r
+}
@@ -0,0 +1,44 @@
+package routers
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	applicationcreate "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/application/create"
+	applicationget "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/application/get"
+	applicationlist "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/application/list"
+	applicationremove "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/application/remove"
+	applicationsearch "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/application/search"
+	applicationupdate "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/application/update"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+)
+
+type applicationManager interface {
+	CreateApplication(ctx context.Context, ownerID int64, app models.Application) (int64, error)
+	Application(ctx context.Context, ownerID, id int64) (models.Application, error)
+	Applications(ctx context.Context, ownerID int64, q models.ApplicationQuery) (models.ApplicationPage, error)
+	SearchApplications(ctx context.Context, ownerID int64, q string, limit int) ([]models.ApplicationSearchHit, error)
+	UpdateApplication(ctx context.Context, ownerID int64, app models.Application) error
+	DeleteApplication(ctx context.Context, ownerID, id int64) error
+}
+
+func NewApplicationRoutes(
+	ctx context.Context,
+	log *slog.Logger,
+	applicationManager applicationManager,
+	phaseManager phaseManager,
+) chi.Router {
+	read := auth.RequireScope(log, models.ScopeApplicationsRead)
+	write := auth.RequireScope(log, models.ScopeApplicationsWrite)
+
+	r := chi.NewRouter()
+	r.With(write).Post("/", applicationcreate.New(ctx, log, applicationManager))
+	r.With(read).Get("/", applicationlist.New(ctx, log, applicationManager))
+	r.With(read).Get("/search", applicationsearch.New(ctx, log, applicationManager))
+	r.With(read).Get("/{id}", applicationget.New(ctx, log, applicationManager))
+	r.With(write).Put("/{id}", applicationupdate.New(ctx, log, applicationManager))
+	r.With(write).Delete("/{id}", applicationremove.New(ctx, log, applicationManager))
+	r.Mount("/{id}/phases", NewPhaseRoutes(ctx, log, phaseManager))
+	return r
+}
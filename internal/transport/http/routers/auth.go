@@ -3,20 +3,74 @@ package routers
 import (
 	"context"
 	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/oidc/callback"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/oidc/start"
 	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/create"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/forgotpassword"
 	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/login"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/logout"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/logoutall"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/refresh"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/resendverification"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/resetpassword"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/twofactor"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/user/verify"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
 	"github.com/go-chi/chi/v5"
 	"log/slog"
 )
 
 type userManager interface {
-	CreateUser(ctx context.Context, email, password, name string) (int64, error)
-	Login(ctx context.Context, email, password string) (models.Tokens, error)
+	CreateUser(ctx context.Context, email, password, name, locale string) (int64, error)
+	Refresh(ctx context.Context, refreshToken string, client models.Client) (models.Tokens, error)
+	Authenticate(ctx context.Context, accessToken string) (models.TokenClaims, error)
+	Logout(ctx context.Context, claims models.TokenClaims) error
+	LogoutAll(ctx context.Context, claims models.TokenClaims) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
 }
 
-func NewAuthRoutes(ctx context.Context, log *slog.Logger, userManager userManager) chi.Router {
+type loginManager interface {
+	Login(ctx context.Context, email, password string, client models.Client) (models.Tokens, error)
+	LoginTwoFactor(ctx context.Context, mfaToken, code string, client models.Client) (models.Tokens, error)
+}
+
+type passwordResetManager interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+}
+
+type oidcManager interface {
+	StartOIDC(ctx context.Context, provider string) (string, string, error)
+	FinishOIDC(ctx context.Context, provider, stateToken, state, code string, client models.Client) (models.Tokens, error)
+}
+
+func NewAuthRoutes(
+	ctx context.Context,
+	log *slog.Logger,
+	userManager userManager,
+	loginManager loginManager,
+	passwordResetManager passwordResetManager,
+	oidcManager oidcManager,
+) chi.Router {
 	r := chi.NewRouter()
 	r.Post("/register", create.New(ctx, log, userManager))
-	r.Post("/login", login.New(ctx, log, userManager))
+	r.Post("/login", login.New(ctx, log, loginManager))
+	r.Post("/2fa", twofactor.New(ctx, log, loginManager))
+	r.Post("/refresh", refresh.New(ctx, log, userManager))
+	r.Post("/verify", verify.New(ctx, log, userManager))
+	r.Post("/resend-verification", resendverification.New(ctx, log, userManager))
+	r.Post("/forgot-password", forgotpassword.New(ctx, log, passwordResetManager))
+	r.Post("/reset-password", resetpassword.New(ctx, log, passwordResetManager))
+	r.Get("/oidc/{provider}/start", start.New(ctx, log, oidcManager))
+	r.Get("/oidc/{provider}/callback", callback.New(ctx, log, oidcManager))
+
+	r.Group(func(r chi.Router) {
+		r.Use(auth.NewJWTMiddleware(log, userManager))
+
+		r.Post("/logout", logout.New(ctx, log, userManager))
+		r.Post("/logout-all", logoutall.New(ctx, log, userManager))
+	})
+
 	return r
 }
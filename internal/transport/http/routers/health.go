@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/lib/buildinfo"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/health/liveness"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/health/readiness"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/health/version"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+)
+
+type readinessChecker interface {
+	Ready(ctx context.Context) error
+}
+
+// NewHealthRoutes serves probes for orchestrators. It is mounted at the root,
+// outside of /api, and doesn't use any of the API middlewares.
+func NewHealthRoutes(log *slog.Logger, readinessChecker readinessChecker) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/healthz", liveness.New())
+	r.Get("/readyz", readiness.New(log, readinessChecker))
+	r.Get("/version", version.New(buildinfo.Get()))
+	return r
+}
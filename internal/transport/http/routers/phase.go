@@ -0,0 +1,36 @@
+package routers
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	phasecreate "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/phase/create"
+	phaselist "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/phase/list"
+	phaseremove "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/phase/remove"
+	phasereorder "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/phase/reorder"
+	phaseupdate "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/phase/update"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+)
+
+type phaseManager interface {
+	AddPhase(ctx context.Context, ownerID int64, phase models.ApplicationPhase) (int64, error)
+	Phases(ctx context.Context, ownerID, applicationID int64) ([]models.ApplicationPhase, error)
+	UpdatePhase(ctx context.Context, ownerID int64, phase models.ApplicationPhase) error
+	DeletePhase(ctx context.Context, ownerID, applicationID, id int64) error
+	ReorderPhases(ctx context.Context, ownerID, applicationID int64, ids []int64) error
+}
+
+// NewPhaseRoutes is mounted under /applications/{id}/phases.
+func NewPhaseRoutes(ctx context.Context, log *slog.Logger, phaseManager phaseManager) chi.Router {
+	read := auth.RequireScope(log, models.ScopeApplicationsRead)
+	write := auth.RequireScope(log, models.ScopeApplicationsWrite)
+
+	r := chi.NewRouter()
+	r.With(write).Post("/", phasecreate.New(ctx, log, phaseManager))
+	r.With(read).Get("/", phaselist.New(ctx, log, phaseManager))
+	r.With(write).Put("/order", phasereorder.New(ctx, log, phaseManager))
+	r.With(write).Put("/{phaseID}", phaseupdate.New(ctx, log, phaseManager))
+	r.With(write).Delete("/{phaseID}", phaseremove.New(ctx, log, phaseManager))
+	return r
+}
@@ -0,0 +1,46 @@
+package routers
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	profilechangepassword "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/profile/changepassword"
+	profileget "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/profile/get"
+	profileremove "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/profile/remove"
+	profileupdate "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/profile/update"
+	twofactorconfirm "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/twofactor/confirm"
+	twofactordisable "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/twofactor/disable"
+	twofactorsetup "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/twofactor/setup"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+)
+
+type profileManager interface {
+	Profile(ctx context.Context, userID int64) (models.User, error)
+	UpdateProfile(ctx context.Context, userID int64, upd models.UserUpdate) (models.User, error)
+	ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string, client models.Client) (models.Tokens, error)
+	DeleteAccount(ctx context.Context, userID int64) error
+	SetupTwoFactor(ctx context.Context, userID int64) (models.TOTPSetup, error)
+	ConfirmTwoFactor(ctx context.Context, userID int64, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID int64, code string) error
+}
+
+// NewProfileRoutes is mounted under /me, the profile of the authenticated user.
+func NewProfileRoutes(
+	ctx context.Context,
+	log *slog.Logger,
+	profileManager profileManager,
+	apiKeyManager apiKeyManager,
+	sessionManager sessionManager,
+) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", profileget.New(ctx, log, profileManager))
+	r.Patch("/", profileupdate.New(ctx, log, profileManager))
+	r.Delete("/", profileremove.New(ctx, log, profileManager))
+	r.Post("/password", profilechangepassword.New(ctx, log, profileManager))
+	r.Post("/2fa/setup", twofactorsetup.New(ctx, log, profileManager))
+	r.Post("/2fa/confirm", twofactorconfirm.New(ctx, log, profileManager))
+	r.Post("/2fa/disable", twofactordisable.New(ctx, log, profileManager))
+	r.Mount("/api-keys", NewAPIKeyRoutes(ctx, log, apiKeyManager))
+	r.Mount("/sessions", NewSessionRoutes(ctx, log, sessionManager))
+	return r
+}
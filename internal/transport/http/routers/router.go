@@ -2,24 +2,70 @@ package routers
 
 import (
 	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/auth"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/middleware/ratelimit"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"log/slog"
 )
 
-func NewAPIRouter(
-	ctx context.Context,
-	log *slog.Logger,
-	userManager userManager,
-) chi.Router {
+type rateLimiter interface {
+	Allow(ctx context.Context, client, method, path string) (models.RateLimitResult, error)
+}
+
+type userService interface {
+	userManager
+	profileManager
+}
+
+// APIDependencies are the usecases the API is served by.
+type APIDependencies struct {
+	// Users manages accounts and the profile of the authenticated user.
+	Users         userService
+	Logins        loginManager
+	PasswordReset passwordResetManager
+	OIDC          oidcManager
+	APIKeys       apiKeyManager
+	Sessions      sessionManager
+	Applications  applicationManager
+	Phases        phaseManager
+	RateLimiter   rateLimiter
+}
+
+// NewAPIRouter serves the API. Rate limits apply before authentication by client address,
+// so that requests with bad credentials are counted too, and again after it by user,
+// so that authenticated users have limits of their own.
+// Request ids and client addresses are set up by the root router.
+func NewAPIRouter(ctx context.Context, log *slog.Logger, deps APIDependencies) chi.Router {
 	r := chi.NewRouter()
 
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.URLFormat)
 
-	r.Mount("/auth", NewAuthRoutes(ctx, log, userManager))
+	rateLimit := ratelimit.New(log, deps.RateLimiter)
+
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimit)
+
+		r.Mount("/auth", NewAuthRoutes(ctx, log, deps.Users, deps.Logins, deps.PasswordReset, deps.OIDC))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimit)
+		r.Use(auth.NewJWTMiddleware(log, deps.Users))
+		r.Use(rateLimit)
+
+		r.Mount("/me", NewProfileRoutes(ctx, log, deps.Users, deps.APIKeys, deps.Sessions))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimit)
+		r.Use(auth.New(log, deps.Users, deps.APIKeys))
+		r.Use(rateLimit)
+
+		r.Mount("/applications", NewApplicationRoutes(ctx, log, deps.Applications, deps.Phases))
+	})
 
 	return r
 }
@@ -0,0 +1,76 @@
+package routers_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/routers"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// rejectingUsers turns down every access token.
+type rejectingUsers struct {
+	*usecase.UserUsecase
+	authenticated int
+}
+
+func (u *rejectingUsers) Authenticate(context.Context, string) (models.TokenClaims, error) {
+	u.authenticated++
+
+	return models.TokenClaims{}, usecase.ErrInvalidToken
+}
+
+// recordingLimiter records the clients it is asked about.
+type recordingLimiter struct {
+	allowed bool
+	clients []string
+}
+
+func (l *recordingLimiter) Allow(_ context.Context, client, _, _ string) (models.RateLimitResult, error) {
+	l.clients = append(l.clients, client)
+
+	return models.RateLimitResult{Allowed: l.allowed, Limit: 10}, nil
+}
+
+func TestNewAPIRouter_RateLimitsBadCredentials(t *testing.T) {
+	for _, path := range []string{"/me", "/applications"} {
+		t.Run(path, func(t *testing.T) {
+			for _, tc := range []struct {
+				name     string
+				allowed  bool
+				wantCode int
+				wantAuth int
+			}{
+				{name: "allowed", allowed: true, wantCode: http.StatusUnauthorized, wantAuth: 1},
+				{name: "exceeded", allowed: false, wantCode: http.StatusTooManyRequests, wantAuth: 0},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					users := &rejectingUsers{}
+					limiter := &recordingLimiter{allowed: tc.allowed}
+					log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+					router := routers.NewAPIRouter(context.Background(), log, routers.APIDependencies{
+						Users:       users,
+						RateLimiter: limiter,
+					})
+
+					r := httptest.NewRequest(http.MethodGet, path, nil)
+					r.RemoteAddr = "192.0.2.1:1234"
+					r.Header.Set("Authorization", "Bearer bad")
+
+					rec := httptest.NewRecorder()
+					router.ServeHTTP(rec, r)
+
+					assert.Equal(t, tc.wantCode, rec.Code)
+					assert.Equal(t, []string{"ip:192.0.2.1"}, limiter.clients)
+					assert.Equal(t, tc.wantAuth, users.authenticated)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	sessionlist "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/session/list"
+	sessionremove "github.com/diproducts/application-tracker-go/internal/transport/http/handlers/session/remove"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+)
+
+type sessionManager interface {
+	Sessions(ctx context.Context, claims models.TokenClaims) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID int64, id string) error
+}
+
+// NewSessionRoutes is mounted under /me/sessions, the devices the user is logged in on.
+func NewSessionRoutes(ctx context.Context, log *slog.Logger, sessionManager sessionManager) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", sessionlist.New(ctx, log, sessionManager))
+	r.Delete("/{id}", sessionremove.New(ctx, log, sessionManager))
+	return r
+}
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/transport/http/handlers/wellknown/jwks"
+	"github.com/go-chi/chi/v5"
+)
+
+type keySet interface {
+	JWKS() tokenutil.JWKS
+}
+
+// NewWellKnownRoutes serves the metadata other services discover at /.well-known.
+// Like the health routes it is mounted at the root, outside of /api.
+func NewWellKnownRoutes(keySet keySet) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/jwks.json", jwks.New(keySet))
+	return r
+}
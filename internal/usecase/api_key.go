@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+var (
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrAPIKeyAlreadyExists = errors.New("api key already exists")
+	ErrInvalidScope        = errors.New("invalid scope")
+)
+
+const (
+	// apiKeyPrefix marks the keys of the service, so leaked ones are easy to recognize.
+	apiKeyPrefix = "atk_"
+	// apiKeyVisibleLength is how many leading characters of a key are stored in clear to tell the keys apart.
+	apiKeyVisibleLength = len(apiKeyPrefix) + 8
+)
+
+type apiKeyRepository interface {
+	SaveAPIKey(ctx context.Context, key *models.APIKey, keyHash string) (int64, error)
+	APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	UseAPIKey(ctx context.Context, keyHash string, at time.Time) (models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id, userID int64) error
+}
+
+type APIKeyUsecase struct {
+	apiKeyRepository apiKeyRepository
+	logger           *slog.Logger
+}
+
+func NewAPIKeyUsecase(apiKeyRepository apiKeyRepository, logger *slog.Logger) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		apiKeyRepository: apiKeyRepository,
+		logger:           logger,
+	}
+}
+
+// CreateAPIKey creates a named key of the user granted the scopes.
+// Returns the stored key and the secret key itself, which is not stored and can't be shown again.
+func (u *APIKeyUsecase) CreateAPIKey(
+	ctx context.Context,
+	userID int64,
+	name string,
+	scopes []string,
+) (models.APIKey, string, error) {
+	const op = "usecase.CreateAPIKey"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		log.Info("invalid scopes", sl.Err(err))
+
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, _, err := tokenutil.NewOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate api key", sl.Err(err))
+
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret := apiKeyPrefix + token
+
+	key := models.APIKey{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  secret[:apiKeyVisibleLength],
+		Scopes:  scopes,
+		Created: time.Now().UTC(),
+	}
+
+	key.ID, err = u.apiKeyRepository.SaveAPIKey(ctx, &key, tokenutil.HashOpaqueToken(secret))
+	if err != nil {
+		if errors.Is(err, storage.ErrAPIKeyAlreadyExists) {
+			log.Info("api key already exists", slog.String("name", name))
+
+			return models.APIKey{}, "", fmt.Errorf("%s: %w", op, ErrAPIKeyAlreadyExists)
+		}
+
+		log.Error("failed to save api key", sl.Err(err))
+
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("api key created", slog.Int64("id", key.ID))
+
+	return key, secret, nil
+}
+
+// APIKeys returns the keys of the user without their secrets.
+func (u *APIKeyUsecase) APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	const op = "usecase.APIKeys"
+
+	keys, err := u.apiKeyRepository.APIKeys(ctx, userID)
+	if err != nil {
+		u.logger.Error("failed to get api keys", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey deletes the key of the user, it stops working immediately.
+func (u *APIKeyUsecase) RevokeAPIKey(ctx context.Context, userID, id int64) error {
+	const op = "usecase.RevokeAPIKey"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID), slog.Int64("id", id))
+
+	if err := u.apiKeyRepository.DeleteAPIKey(ctx, id, userID); err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			log.Info("api key not found")
+
+			return fmt.Errorf("%s: %w", op, ErrAPIKeyNotFound)
+		}
+
+		log.Error("failed to delete api key", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("api key revoked")
+
+	return nil
+}
+
+// AuthenticateAPIKey validates the key and returns the claims of its user,
+// restricted to the scopes of the key. It records the use of the key.
+func (u *APIKeyUsecase) AuthenticateAPIKey(ctx context.Context, secret string) (models.TokenClaims, error) {
+	const op = "usecase.AuthenticateAPIKey"
+
+	log := u.logger.With(slog.String("op", op))
+
+	key, err := u.apiKeyRepository.UseAPIKey(ctx, tokenutil.HashOpaqueToken(secret), time.Now())
+	if err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			log.Info("unknown api key")
+
+			return models.TokenClaims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get api key", sl.Err(err))
+
+		return models.TokenClaims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.TokenClaims{
+		UserID:   key.UserID,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// normalizeScopes checks that the scopes exist and returns them without duplicates in a stable order.
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(models.APIKeyScopes, scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range models.APIKeyScopes {
+		if slices.Contains(scopes, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,90 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeyUsecase(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	k := usecase.NewAPIKeyUsecase(memory.NewAPIKeyRepository(db), newLogger())
+
+	userID, _, _ := createUser(t, u)
+
+	key, secret, err := k.CreateAPIKey(ctx, userID, "importer", []string{
+		models.ScopeApplicationsWrite,
+		models.ScopeApplicationsRead,
+		models.ScopeApplicationsWrite,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, key.ID)
+	assert.True(t, strings.HasPrefix(secret, key.Prefix))
+	assert.Equal(t, []string{models.ScopeApplicationsRead, models.ScopeApplicationsWrite}, key.Scopes)
+	assert.Nil(t, key.LastUsedAt)
+
+	_, _, err = k.CreateAPIKey(ctx, userID, "importer", []string{models.ScopeApplicationsRead})
+	assert.ErrorIs(t, err, usecase.ErrAPIKeyAlreadyExists)
+
+	_, _, err = k.CreateAPIKey(ctx, userID, "admin", []string{"users:write"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidScope)
+
+	_, _, err = k.CreateAPIKey(ctx, userID, "nothing", nil)
+	assert.ErrorIs(t, err, usecase.ErrInvalidScope)
+
+	claims, err := k.AuthenticateAPIKey(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, key.ID, claims.APIKeyID)
+	assert.True(t, claims.HasScope(models.ScopeApplicationsWrite))
+
+	_, err = k.AuthenticateAPIKey(ctx, secret+"x")
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	keys, err := k.APIKeys(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "importer", keys[0].Name)
+	assert.NotNil(t, keys[0].LastUsedAt)
+
+	otherID, _, _ := createUser(t, u)
+
+	assert.ErrorIs(t, k.RevokeAPIKey(ctx, otherID, key.ID), usecase.ErrAPIKeyNotFound)
+
+	require.NoError(t, k.RevokeAPIKey(ctx, userID, key.ID))
+
+	_, err = k.AuthenticateAPIKey(ctx, secret)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
+
+func TestAPIKeyUsecase_Scopes(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	k := usecase.NewAPIKeyUsecase(memory.NewAPIKeyRepository(db), newLogger())
+
+	userID, email, password := createUser(t, u)
+
+	_, secret, err := k.CreateAPIKey(ctx, userID, "reader", []string{models.ScopeApplicationsRead})
+	require.NoError(t, err)
+
+	claims, err := k.AuthenticateAPIKey(ctx, secret)
+	require.NoError(t, err)
+	assert.True(t, claims.HasScope(models.ScopeApplicationsRead))
+	assert.False(t, claims.HasScope(models.ScopeApplicationsWrite))
+
+	// Access tokens aren't limited by scopes.
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	claims, err = u.Authenticate(ctx, tokens.Access)
+	require.NoError(t, err)
+	assert.True(t, claims.HasScope(models.ScopeApplicationsWrite))
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+)
+
+var (
+	ErrApplicationNotFound = errors.New("application not found")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type applicationRepository interface {
+	SaveApplication(ctx context.Context, app *models.Application) (int64, error)
+	Application(ctx context.Context, id, ownerID int64) (models.Application, error)
+	Applications(ctx context.Context, ownerID int64, q models.ApplicationQuery) (models.ApplicationPage, error)
+	SearchApplications(ctx context.Context, ownerID int64, q string, limit int) ([]models.ApplicationSearchHit, error)
+	UpdateApplication(ctx context.Context, app *models.Application) error
+	DeleteApplication(ctx context.Context, id, ownerID int64) error
+}
+
+type ApplicationUsecase struct {
+	applicationRepository applicationRepository
+	logger                *slog.Logger
+}
+
+func NewApplicationUsecase(
+	applicationRepository applicationRepository,
+	logger *slog.Logger,
+) *ApplicationUsecase {
+	return &ApplicationUsecase{
+		applicationRepository: applicationRepository,
+		logger:                logger,
+	}
+}
+
+// CreateApplication stores a new application owned by ownerID.
+// Returns an id of the created application and error.
+func (u *ApplicationUsecase) CreateApplication(
+	ctx context.Context,
+	ownerID int64,
+	app models.Application,
+) (int64, error) {
+	const op = "usecase.CreateApplication"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	app.OwnerID = ownerID
+
+	id, err := u.applicationRepository.SaveApplication(ctx, &app)
+	if err != nil {
+		log.Error("failed to save application", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Application returns the application with the given id if it belongs to ownerID.
+func (u *ApplicationUsecase) Application(ctx context.Context, ownerID, id int64) (models.Application, error) {
+	const op = "usecase.Application"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	app, err := u.applicationRepository.Application(ctx, id, ownerID)
+	if err != nil {
+		if errors.Is(err, storage.ErrApplicationNotFound) {
+			log.Info("application not found", slog.Int64("id", id))
+
+			return models.Application{}, fmt.Errorf("%s: %w", op, ErrApplicationNotFound)
+		}
+
+		log.Error("failed to get application", sl.Err(err))
+
+		return models.Application{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// Applications returns a page of applications that belong to ownerID.
+// The page size defaults to DefaultPageSize and is capped at MaxPageSize.
+func (u *ApplicationUsecase) Applications(
+	ctx context.Context,
+	ownerID int64,
+	q models.ApplicationQuery,
+) (models.ApplicationPage, error) {
+	const op = "usecase.Applications"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	if q.Limit <= 0 {
+		q.Limit = DefaultPageSize
+	}
+	q.Limit = min(q.Limit, MaxPageSize)
+
+	page, err := u.applicationRepository.Applications(ctx, ownerID, q)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidCursor) {
+			log.Info("invalid cursor")
+
+			return models.ApplicationPage{}, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+		}
+
+		log.Error("failed to list applications", sl.Err(err))
+
+		return models.ApplicationPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// SearchApplications returns at most limit applications that belong to ownerID
+// matching the search query q, best matches first.
+// The limit defaults to DefaultPageSize and is capped at MaxPageSize.
+func (u *ApplicationUsecase) SearchApplications(
+	ctx context.Context,
+	ownerID int64,
+	q string,
+	limit int,
+) ([]models.ApplicationSearchHit, error) {
+	const op = "usecase.SearchApplications"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	hits, err := u.applicationRepository.SearchApplications(ctx, ownerID, q, limit)
+	if err != nil {
+		log.Error("failed to search applications", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, nil
+}
+
+// UpdateApplication replaces the editable fields of the application app.ID
+// if it belongs to ownerID.
+func (u *ApplicationUsecase) UpdateApplication(ctx context.Context, ownerID int64, app models.Application) error {
+	const op = "usecase.UpdateApplication"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	app.OwnerID = ownerID
+
+	err := u.applicationRepository.UpdateApplication(ctx, &app)
+	if err != nil {
+		if errors.Is(err, storage.ErrApplicationNotFound) {
+			log.Info("application not found", slog.Int64("id", app.ID))
+
+			return fmt.Errorf("%s: %w", op, ErrApplicationNotFound)
+		}
+
+		log.Error("failed to update application", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteApplication removes the application with the given id if it belongs to ownerID.
+func (u *ApplicationUsecase) DeleteApplication(ctx context.Context, ownerID, id int64) error {
+	const op = "usecase.DeleteApplication"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	err := u.applicationRepository.DeleteApplication(ctx, id, ownerID)
+	if err != nil {
+		if errors.Is(err, storage.ErrApplicationNotFound) {
+			log.Info("application not found", slog.Int64("id", id))
+
+			return fmt.Errorf("%s: %w", op, ErrApplicationNotFound)
+		}
+
+		log.Error("failed to delete application", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
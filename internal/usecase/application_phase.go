@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+)
+
+var (
+	ErrPhaseNotFound = errors.New("application phase not found")
+)
+
+type phaseRepository interface {
+	SavePhase(ctx context.Context, ownerID int64, phase *models.ApplicationPhase) (int64, error)
+	Phases(ctx context.Context, ownerID, applicationID int64) ([]models.ApplicationPhase, error)
+	UpdatePhase(ctx context.Context, ownerID int64, phase *models.ApplicationPhase) error
+	DeletePhase(ctx context.Context, ownerID, applicationID, id int64) error
+	ReorderPhases(ctx context.Context, ownerID, applicationID int64, ids []int64) error
+}
+
+type ApplicationPhaseUsecase struct {
+	phaseRepository phaseRepository
+	logger          *slog.Logger
+}
+
+func NewApplicationPhaseUsecase(
+	phaseRepository phaseRepository,
+	logger *slog.Logger,
+) *ApplicationPhaseUsecase {
+	return &ApplicationPhaseUsecase{
+		phaseRepository: phaseRepository,
+		logger:          logger,
+	}
+}
+
+// AddPhase appends a new phase to the timeline of the application phase.ApplicationID.
+// Returns an id of the created phase and error.
+func (u *ApplicationPhaseUsecase) AddPhase(
+	ctx context.Context,
+	ownerID int64,
+	phase models.ApplicationPhase,
+) (int64, error) {
+	const op = "usecase.AddPhase"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	id, err := u.phaseRepository.SavePhase(ctx, ownerID, &phase)
+	if err != nil {
+		return 0, u.mapError(log, op, "failed to save phase", err)
+	}
+
+	return id, nil
+}
+
+// Phases returns the timeline of the application ordered by date.
+func (u *ApplicationPhaseUsecase) Phases(
+	ctx context.Context,
+	ownerID, applicationID int64,
+) ([]models.ApplicationPhase, error) {
+	const op = "usecase.Phases"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	phases, err := u.phaseRepository.Phases(ctx, ownerID, applicationID)
+	if err != nil {
+		return nil, u.mapError(log, op, "failed to list phases", err)
+	}
+
+	return phases, nil
+}
+
+// UpdatePhase replaces name, date and notes of the phase.
+func (u *ApplicationPhaseUsecase) UpdatePhase(ctx context.Context, ownerID int64, phase models.ApplicationPhase) error {
+	const op = "usecase.UpdatePhase"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	if err := u.phaseRepository.UpdatePhase(ctx, ownerID, &phase); err != nil {
+		return u.mapError(log, op, "failed to update phase", err)
+	}
+
+	return nil
+}
+
+// DeletePhase removes the phase from the application timeline.
+func (u *ApplicationPhaseUsecase) DeletePhase(ctx context.Context, ownerID, applicationID, id int64) error {
+	const op = "usecase.DeletePhase"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	if err := u.phaseRepository.DeletePhase(ctx, ownerID, applicationID, id); err != nil {
+		return u.mapError(log, op, "failed to delete phase", err)
+	}
+
+	return nil
+}
+
+// ReorderPhases changes the manual order of the phases that share the same date.
+func (u *ApplicationPhaseUsecase) ReorderPhases(ctx context.Context, ownerID, applicationID int64, ids []int64) error {
+	const op = "usecase.ReorderPhases"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("owner_id", ownerID))
+
+	if err := u.phaseRepository.ReorderPhases(ctx, ownerID, applicationID, ids); err != nil {
+		return u.mapError(log, op, "failed to reorder phases", err)
+	}
+
+	return nil
+}
+
+// mapError converts storage errors into usecase errors and logs unexpected ones.
+func (u *ApplicationPhaseUsecase) mapError(log *slog.Logger, op, msg string, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrApplicationNotFound):
+		log.Info("application not found")
+
+		return fmt.Errorf("%s: %w", op, ErrApplicationNotFound)
+	case errors.Is(err, storage.ErrPhaseNotFound):
+		log.Info("phase not found")
+
+		return fmt.Errorf("%s: %w", op, ErrPhaseNotFound)
+	}
+
+	log.Error(msg, sl.Err(err))
+
+	return fmt.Errorf("%s: %w", op, err)
+}
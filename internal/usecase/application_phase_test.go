@@ -0,0 +1,122 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestApplicationPhaseUsecase_Timeline(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	ownerID, _, _ := createUser(t, newUserUsecase(db))
+	apps := usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), newLogger())
+	u := usecase.NewApplicationPhaseUsecase(memory.NewApplicationPhaseRepository(db), newLogger())
+
+	appID, err := apps.CreateApplication(ctx, ownerID, fakeApplication())
+	require.NoError(t, err)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	interviewID, err := u.AddPhase(ctx, ownerID, models.ApplicationPhase{
+		Name:          models.PhaseInterview,
+		Date:          day.AddDate(0, 0, 7),
+		ApplicationID: appID,
+	})
+	require.NoError(t, err)
+	appliedID, err := u.AddPhase(ctx, ownerID, models.ApplicationPhase{
+		Name:          models.PhaseApplied,
+		Date:          day,
+		ApplicationID: appID,
+	})
+	require.NoError(t, err)
+	screeningID, err := u.AddPhase(ctx, ownerID, models.ApplicationPhase{
+		Name:          models.PhaseScreening,
+		Date:          day,
+		ApplicationID: appID,
+	})
+	require.NoError(t, err)
+
+	phases, err := u.Phases(ctx, ownerID, appID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{appliedID, screeningID, interviewID}, phaseIDs(phases))
+
+	// Phases of the same day keep the manual order.
+	require.NoError(t, u.ReorderPhases(ctx, ownerID, appID, []int64{screeningID, appliedID}))
+
+	phases, err = u.Phases(ctx, ownerID, appID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{screeningID, appliedID, interviewID}, phaseIDs(phases))
+
+	err = u.ReorderPhases(ctx, ownerID, appID, []int64{appliedID, interviewID + 100})
+	assert.ErrorIs(t, err, usecase.ErrPhaseNotFound)
+
+	require.NoError(t, u.UpdatePhase(ctx, ownerID, models.ApplicationPhase{
+		ID:            interviewID,
+		Name:          models.PhaseOnsite,
+		Date:          day.AddDate(0, 0, 8),
+		Notes:         "second round",
+		ApplicationID: appID,
+	}))
+	require.NoError(t, u.DeletePhase(ctx, ownerID, appID, appliedID))
+
+	phases, err = u.Phases(ctx, ownerID, appID)
+	require.NoError(t, err)
+	require.Len(t, phases, 2)
+	assert.Equal(t, models.PhaseOnsite, phases[1].Name)
+	assert.Equal(t, "second round", phases[1].Notes)
+
+	err = u.DeletePhase(ctx, ownerID, appID, appliedID)
+	assert.ErrorIs(t, err, usecase.ErrPhaseNotFound)
+
+	// Deleting the application removes its timeline.
+	require.NoError(t, apps.DeleteApplication(ctx, ownerID, appID))
+
+	_, err = u.Phases(ctx, ownerID, appID)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+}
+
+func TestApplicationPhaseUsecase_OtherOwner(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	users := newUserUsecase(db)
+	ownerID, _, _ := createUser(t, users)
+	otherID, _, _ := createUser(t, users)
+	apps := usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), newLogger())
+	u := usecase.NewApplicationPhaseUsecase(memory.NewApplicationPhaseRepository(db), newLogger())
+
+	appID, err := apps.CreateApplication(ctx, ownerID, fakeApplication())
+	require.NoError(t, err)
+
+	phase := models.ApplicationPhase{Name: models.PhaseApplied, Date: time.Now(), ApplicationID: appID}
+
+	phaseID, err := u.AddPhase(ctx, ownerID, phase)
+	require.NoError(t, err)
+
+	_, err = u.AddPhase(ctx, otherID, phase)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+
+	_, err = u.Phases(ctx, otherID, appID)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+
+	phase.ID = phaseID
+	err = u.UpdatePhase(ctx, otherID, phase)
+	assert.ErrorIs(t, err, usecase.ErrPhaseNotFound)
+
+	err = u.DeletePhase(ctx, otherID, appID, phaseID)
+	assert.ErrorIs(t, err, usecase.ErrPhaseNotFound)
+}
+
+func phaseIDs(phases []models.ApplicationPhase) []int64 {
+	ids := make([]int64, 0, len(phases))
+	for _, p := range phases {
+		ids = append(ids, p.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,204 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func fakeApplication() models.Application {
+	return models.Application{
+		CompanyName:   gofakeit.Company(),
+		Position:      gofakeit.JobTitle(),
+		Url:           gofakeit.URL(),
+		OfferedSalary: gofakeit.Number(1000, 10000),
+	}
+}
+
+func TestApplicationUsecase_CRUD(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	ownerID, _, _ := createUser(t, newUserUsecase(db))
+	u := usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), newLogger())
+
+	app := fakeApplication()
+
+	id, err := u.CreateApplication(ctx, ownerID, app)
+	require.NoError(t, err)
+
+	got, err := u.Application(ctx, ownerID, id)
+	require.NoError(t, err)
+	assert.Equal(t, app.CompanyName, got.CompanyName)
+	assert.Equal(t, app.OfferedSalary, got.OfferedSalary)
+	assert.Equal(t, ownerID, got.OwnerID)
+	assert.False(t, got.Created.IsZero())
+
+	update := fakeApplication()
+	update.ID = id
+	require.NoError(t, u.UpdateApplication(ctx, ownerID, update))
+
+	got, err = u.Application(ctx, ownerID, id)
+	require.NoError(t, err)
+	assert.Equal(t, update.Position, got.Position)
+	assert.False(t, got.LastModified.Before(got.Created))
+
+	page, err := u.Applications(ctx, ownerID, models.ApplicationQuery{})
+	require.NoError(t, err)
+	assert.Len(t, page.Applications, 1)
+	assert.Empty(t, page.NextCursor)
+
+	require.NoError(t, u.DeleteApplication(ctx, ownerID, id))
+
+	_, err = u.Application(ctx, ownerID, id)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+
+	err = u.DeleteApplication(ctx, ownerID, id)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+}
+
+func TestApplicationUsecase_OtherOwner(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	users := newUserUsecase(db)
+	ownerID, _, _ := createUser(t, users)
+	otherID, _, _ := createUser(t, users)
+	u := usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), newLogger())
+
+	id, err := u.CreateApplication(ctx, ownerID, fakeApplication())
+	require.NoError(t, err)
+
+	_, err = u.Application(ctx, otherID, id)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+
+	update := fakeApplication()
+	update.ID = id
+	err = u.UpdateApplication(ctx, otherID, update)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+
+	err = u.DeleteApplication(ctx, otherID, id)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+
+	page, err := u.Applications(ctx, otherID, models.ApplicationQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Applications)
+}
+
+func TestApplicationUsecase_Applications(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	ownerID, _, _ := createUser(t, newUserUsecase(db))
+	u := usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), newLogger())
+	phases := usecase.NewApplicationPhaseUsecase(memory.NewApplicationPhaseRepository(db), newLogger())
+
+	salaries := []int{3000, 1000, 2000, 1000, 5000, 4000, 1000}
+	for i, salary := range salaries {
+		app := fakeApplication()
+		app.OfferedSalary = salary
+
+		id, err := u.CreateApplication(ctx, ownerID, app)
+		require.NoError(t, err)
+
+		if i%2 == 0 {
+			_, err = phases.AddPhase(ctx, ownerID, models.ApplicationPhase{
+				Name:          models.PhaseInterview,
+				Date:          time.Now(),
+				ApplicationID: id,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	q := models.ApplicationQuery{
+		Sort:  []models.SortOrder{{Field: models.ApplicationSortOfferedSalary, Desc: true}},
+		Limit: 3,
+	}
+
+	var got []int
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, len(salaries), "pagination does not terminate")
+
+		page, err := u.Applications(ctx, ownerID, q)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Applications), q.Limit)
+
+		for _, app := range page.Applications {
+			got = append(got, app.OfferedSalary)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		q.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, []int{5000, 4000, 3000, 2000, 1000, 1000, 1000}, got)
+
+	minSalary, maxSalary := 2000, 4000
+	page, err := u.Applications(ctx, ownerID, models.ApplicationQuery{
+		Filter: models.ApplicationFilter{MinSalary: &minSalary, MaxSalary: &maxSalary},
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Applications, 3)
+
+	page, err = u.Applications(ctx, ownerID, models.ApplicationQuery{
+		Filter: models.ApplicationFilter{Phase: models.PhaseInterview},
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Applications, 4)
+
+	// A cursor is bound to the sort it was created for.
+	_, err = u.Applications(ctx, ownerID, models.ApplicationQuery{Cursor: q.Cursor})
+	assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+
+	_, err = u.Applications(ctx, ownerID, models.ApplicationQuery{Cursor: "garbage"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+}
+
+func TestApplicationUsecase_SearchApplications(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	users := newUserUsecase(db)
+	ownerID, _, _ := createUser(t, users)
+	otherID, _, _ := createUser(t, users)
+	u := usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), newLogger())
+
+	kafka := fakeApplication()
+	kafka.Position = "Go Backend Engineer"
+	kafka.JobDescription = "We stream events through Kafka and write services in Go."
+
+	kafkaID, err := u.CreateApplication(ctx, ownerID, kafka)
+	require.NoError(t, err)
+
+	mention := fakeApplication()
+	mention.Position = "Platform Engineer"
+	mention.CoverLetter = "I have built Kafka consumers in Go backend services."
+
+	mentionID, err := u.CreateApplication(ctx, ownerID, mention)
+	require.NoError(t, err)
+
+	_, err = u.CreateApplication(ctx, ownerID, fakeApplication())
+	require.NoError(t, err)
+	_, err = u.CreateApplication(ctx, otherID, kafka)
+	require.NoError(t, err)
+
+	hits, err := u.SearchApplications(ctx, ownerID, "go backend kafka", 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+
+	// Matches in the position outrank matches in the cover letter.
+	assert.Equal(t, kafkaID, hits[0].ID)
+	assert.Equal(t, mentionID, hits[1].ID)
+	assert.Greater(t, hits[0].Rank, hits[1].Rank)
+	assert.Contains(t, hits[0].Snippet, "<mark>Kafka</mark>")
+
+	hits, err = u.SearchApplications(ctx, ownerID, "kafka rust", 0)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+}
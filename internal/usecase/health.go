@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"log/slog"
+)
+
+var (
+	ErrDatabaseUnavailable = errors.New("database unavailable")
+	ErrMigrationsPending   = errors.New("migrations pending")
+)
+
+type dbPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type migrationChecker interface {
+	HasPending(ctx context.Context) (bool, error)
+}
+
+type HealthUsecase struct {
+	db               dbPinger
+	migrationChecker migrationChecker
+	logger           *slog.Logger
+}
+
+func NewHealthUsecase(db dbPinger, migrationChecker migrationChecker, logger *slog.Logger) *HealthUsecase {
+	return &HealthUsecase{
+		db:               db,
+		migrationChecker: migrationChecker,
+		logger:           logger,
+	}
+}
+
+// Ready checks that the database is reachable and its schema is up-to-date.
+func (u *HealthUsecase) Ready(ctx context.Context) error {
+	const op = "usecase.Ready"
+
+	log := u.logger.With(slog.String("op", op))
+
+	if err := u.db.PingContext(ctx); err != nil {
+		log.Warn("database ping failed", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrDatabaseUnavailable)
+	}
+
+	pending, err := u.migrationChecker.HasPending(ctx)
+	if err != nil {
+		log.Warn("failed to check migrations", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrDatabaseUnavailable)
+	}
+
+	if pending {
+		log.Warn("database has pending migrations")
+
+		return fmt.Errorf("%s: %w", op, ErrMigrationsPending)
+	}
+
+	return nil
+}
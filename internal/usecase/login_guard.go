@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrTooManyAttempts = errors.New("too many failed login attempts")
+
+// LockoutError is returned while logins are locked out. It wraps ErrTooManyAttempts.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, e.RetryAfter)
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+type loginAttemptRepository interface {
+	ReserveLoginAttempt(
+		ctx context.Context,
+		key string,
+		at time.Time,
+		since time.Time,
+		maxAttempts int,
+		lockUntil time.Time,
+	) (models.LoginAttempts, bool, error)
+	ReleaseLoginAttempt(ctx context.Context, key string, maxAttempts int) error
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginAttempts(ctx context.Context, key string) error
+	DeleteExpiredLoginAttempts(ctx context.Context, before time.Time) (int64, error)
+}
+
+type loginProvider interface {
+	Login(ctx context.Context, email, password string, client models.Client) (models.Tokens, error)
+	MFATokenUserID(mfaToken string) (int64, error)
+	LoginTwoFactor(ctx context.Context, mfaToken, code string, client models.Client) (models.Tokens, error)
+}
+
+// LockoutOptions configure when failed logins lock out further attempts.
+type LockoutOptions struct {
+	// MaxAttempts is the number of failed logins of an account before it is locked out.
+	// Wrong second factors are counted separately against the same limit.
+	// Zero disables the lockout of accounts.
+	MaxAttempts int
+	// MaxIPAttempts is the same for a client address. It should be higher than
+	// MaxAttempts as many users may share an address. Zero disables it.
+	MaxIPAttempts int
+	// Window is how long failures are remembered after the last one or the end of the lockout.
+	Window time.Duration
+	// Duration is the first lockout. Every further failure doubles it up to MaxDuration.
+	Duration    time.Duration
+	MaxDuration time.Duration
+}
+
+// LoginGuardUsecase protects logins against brute force. Password logins are counted
+// per account and per client address before the password is checked, and once either
+// reaches its limit logins are refused without checking the password until the lockout
+// ends. Counting first keeps concurrent guesses from getting past the limit.
+// Wrong second factors are counted per user the same way.
+type LoginGuardUsecase struct {
+	loginProvider          loginProvider
+	loginAttemptRepository loginAttemptRepository
+	options                LockoutOptions
+	logger                 *slog.Logger
+}
+
+func NewLoginGuardUsecase(
+	loginProvider loginProvider,
+	loginAttemptRepository loginAttemptRepository,
+	options LockoutOptions,
+	logger *slog.Logger,
+) *LoginGuardUsecase {
+	return &LoginGuardUsecase{
+		loginProvider:          loginProvider,
+		loginAttemptRepository: loginAttemptRepository,
+		options:                options,
+		logger:                 logger,
+	}
+}
+
+// attemptKey is what failed logins are counted by.
+type attemptKey struct {
+	kind        string
+	key         string
+	maxAttempts int
+}
+
+// Login logs the user in like UserUsecase.Login. Failures are also counted by the address of the client.
+// Returns a *LockoutError while the account or the address is locked out.
+func (u *LoginGuardUsecase) Login(ctx context.Context, email, password string, client models.Client) (models.Tokens, error) {
+	const op = "usecase.LoginGuard.Login"
+
+	log := u.logger.With(slog.String("op", op), slog.String("ip", client.IP))
+
+	keys := u.attemptKeys(email, client.IP)
+	now := time.Now()
+
+	reserved, err := u.reserveAttempts(ctx, log, keys, now)
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := u.loginProvider.Login(ctx, email, password, client)
+	if err == nil {
+		// The address keeps its failures: one valid account mustn't clear the
+		// count of an address trying the passwords of others.
+		for _, k := range keys {
+			if k.kind == "account" {
+				err = u.loginAttemptRepository.ResetLoginAttempts(ctx, k.key)
+			} else {
+				err = u.loginAttemptRepository.ReleaseLoginAttempt(ctx, k.key, k.maxAttempts)
+			}
+			if err != nil {
+				log.Error("failed to reset login attempts", sl.Err(err))
+			}
+		}
+
+		return tokens, nil
+	}
+	if !errors.Is(err, ErrInvalidCredentials) {
+		u.releaseAttempts(ctx, log, keys)
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.recordFailure(ctx, log, keys, reserved); err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+}
+
+// LoginTwoFactor finishes the login like UserUsecase.LoginTwoFactor. Wrong codes are
+// counted per user, so that new mfa tokens don't give an attacker new guesses.
+// Returns a *LockoutError while the second factor of the user is locked out.
+func (u *LoginGuardUsecase) LoginTwoFactor(ctx context.Context, mfaToken, code string, client models.Client) (models.Tokens, error) {
+	const op = "usecase.LoginGuard.LoginTwoFactor"
+
+	log := u.logger.With(slog.String("op", op), slog.String("ip", client.IP))
+
+	userID, err := u.loginProvider.MFATokenUserID(mfaToken)
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", userID))
+
+	var keys []attemptKey
+	if u.options.MaxAttempts > 0 {
+		keys = append(keys, attemptKey{kind: "second_factor", key: secondFactorAttemptKey(userID), maxAttempts: u.options.MaxAttempts})
+	}
+	now := time.Now()
+
+	reserved, err := u.reserveAttempts(ctx, log, keys, now)
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := u.loginProvider.LoginTwoFactor(ctx, mfaToken, code, client)
+	if err == nil {
+		if err := u.loginAttemptRepository.ResetLoginAttempts(ctx, secondFactorAttemptKey(userID)); err != nil {
+			log.Error("failed to reset login attempts", sl.Err(err))
+		}
+
+		return tokens, nil
+	}
+	if !errors.Is(err, ErrInvalidCode) {
+		u.releaseAttempts(ctx, log, keys)
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.recordFailure(ctx, log, keys, reserved); err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+}
+
+// reserveAttempts counts an attempt for every key before it is checked. Returns a
+// *LockoutError if any of the keys is locked out, after releasing the attempts
+// already reserved. Returns the attempts of the keys otherwise.
+func (u *LoginGuardUsecase) reserveAttempts(
+	ctx context.Context,
+	log *slog.Logger,
+	keys []attemptKey,
+	now time.Time,
+) ([]models.LoginAttempts, error) {
+	reserved := make([]models.LoginAttempts, 0, len(keys))
+	for i, k := range keys {
+		attempts, ok, err := u.loginAttemptRepository.ReserveLoginAttempt(
+			ctx,
+			k.key,
+			now,
+			now.Add(-u.options.Window),
+			k.maxAttempts,
+			now.Add(u.options.Duration),
+		)
+		if err != nil {
+			log.Error("failed to reserve login attempt", sl.Err(err))
+			u.releaseAttempts(ctx, log, keys[:i])
+
+			return nil, err
+		}
+
+		if !ok {
+			log.Info("login locked out", slog.String("by", k.kind))
+			u.releaseAttempts(ctx, log, keys[:i])
+
+			retryAfter := u.options.Duration
+			if attempts.LockedUntil != nil {
+				retryAfter = attempts.LockedUntil.Sub(now)
+			}
+
+			return nil, &LockoutError{RetryAfter: retryAfter}
+		}
+
+		reserved = append(reserved, attempts)
+	}
+
+	return reserved, nil
+}
+
+// releaseAttempts takes back the attempts reserved for the keys.
+func (u *LoginGuardUsecase) releaseAttempts(ctx context.Context, log *slog.Logger, keys []attemptKey) {
+	for _, k := range keys {
+		if err := u.loginAttemptRepository.ReleaseLoginAttempt(ctx, k.key, k.maxAttempts); err != nil {
+			log.Error("failed to release login attempt", sl.Err(err))
+		}
+	}
+}
+
+// recordFailure locks out the keys whose reserved attempt reached their limit. The lockout
+// starts now rather than when the attempt was reserved, as checking it may take a while.
+// Returns a *LockoutError if any of them was locked out.
+func (u *LoginGuardUsecase) recordFailure(
+	ctx context.Context,
+	log *slog.Logger,
+	keys []attemptKey,
+	reserved []models.LoginAttempts,
+) error {
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for i, k := range keys {
+		attempts := reserved[i]
+		if attempts.Failures < k.maxAttempts {
+			continue
+		}
+
+		lockout := u.lockout(attempts.Failures - k.maxAttempts)
+		if err := u.loginAttemptRepository.LockLogin(ctx, k.key, now.Add(lockout)); err != nil {
+			log.Error("failed to lock out login", sl.Err(err))
+
+			return err
+		}
+
+		log.Warn(
+			"login locked out after failed attempts",
+			slog.String("by", k.kind),
+			slog.Int("failures", attempts.Failures),
+			slog.Duration("lockout", lockout),
+		)
+
+		retryAfter = max(retryAfter, lockout)
+	}
+
+	if retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// UnlockAccount lifts the lockout of the account and forgets its failed logins.
+func (u *LoginGuardUsecase) UnlockAccount(ctx context.Context, email string) error {
+	const op = "usecase.LoginGuard.UnlockAccount"
+
+	if err := u.loginAttemptRepository.ResetLoginAttempts(ctx, accountAttemptKey(email)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeExpiredAttempts removes failed logins that are no longer counted.
+func (u *LoginGuardUsecase) PurgeExpiredAttempts(ctx context.Context) error {
+	const op = "usecase.LoginGuard.PurgeExpiredAttempts"
+
+	log := u.logger.With(slog.String("op", op))
+
+	n, err := u.loginAttemptRepository.DeleteExpiredLoginAttempts(ctx, time.Now().Add(-u.options.Window))
+	if err != nil {
+		log.Error("failed to purge expired login attempts", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Debug("expired login attempts purged", slog.Int64("count", n))
+
+	return nil
+}
+
+func (u *LoginGuardUsecase) attemptKeys(email, ip string) []attemptKey {
+	var keys []attemptKey
+
+	if u.options.MaxAttempts > 0 {
+		keys = append(keys, attemptKey{kind: "account", key: accountAttemptKey(email), maxAttempts: u.options.MaxAttempts})
+	}
+
+	if u.options.MaxIPAttempts > 0 && ip != "" {
+		keys = append(keys, attemptKey{kind: "ip", key: "ip:" + ip, maxAttempts: u.options.MaxIPAttempts})
+	}
+
+	return keys
+}
+
+// lockout is the lockout after the given number of failures past the limit.
+func (u *LoginGuardUsecase) lockout(excess int) time.Duration {
+	d := u.options.Duration
+	for i := 0; i < excess && d < u.options.MaxDuration; i++ {
+		d *= 2
+	}
+
+	return min(d, u.options.MaxDuration)
+}
+
+func accountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+func secondFactorAttemptKey(userID int64) string {
+	return "2fa:" + strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,230 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var lockoutOptions = usecase.LockoutOptions{
+	MaxAttempts:   3,
+	MaxIPAttempts: 5,
+	Window:        15 * time.Minute,
+	Duration:      time.Minute,
+	MaxDuration:   3 * time.Minute,
+}
+
+func newLoginGuard(db *memory.DB, u *usecase.UserUsecase) *usecase.LoginGuardUsecase {
+	return usecase.NewLoginGuardUsecase(u, memory.NewLoginAttemptRepository(db), lockoutOptions, newLogger())
+}
+
+// assertLockedOut checks that err is a lockout ending in about the given duration.
+func assertLockedOut(t *testing.T, err error, retryAfter time.Duration) {
+	t.Helper()
+
+	var lockoutErr *usecase.LockoutError
+	require.ErrorAs(t, err, &lockoutErr)
+	assert.ErrorIs(t, err, usecase.ErrTooManyAttempts)
+	assert.InDelta(t, retryAfter.Seconds(), lockoutErr.RetryAfter.Seconds(), 1)
+}
+
+func TestLoginGuardUsecase_Account(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	g := newLoginGuard(db, u)
+
+	_, email, password := createUser(t, u)
+
+	for i := 1; i < lockoutOptions.MaxAttempts; i++ {
+		_, err := g.Login(ctx, email, "wrong"+password, models.Client{IP: "10.0.0.1"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+	}
+
+	// A success forgets the failures of the account.
+	_, err := g.Login(ctx, email, password, models.Client{IP: "10.0.0.1"})
+	require.NoError(t, err)
+
+	for i := 1; i < lockoutOptions.MaxAttempts; i++ {
+		_, err := g.Login(ctx, email, "wrong"+password, models.Client{IP: "10.0.0.2"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+	}
+
+	_, err = g.Login(ctx, email, "wrong"+password, models.Client{IP: "10.0.0.3"})
+	assertLockedOut(t, err, time.Minute)
+
+	// The right password doesn't help while locked out, from any address, whatever the case of the email.
+	_, err = g.Login(ctx, strings.ToUpper(email), password, models.Client{IP: "10.0.0.4"})
+	assertLockedOut(t, err, time.Minute)
+
+	require.NoError(t, g.UnlockAccount(ctx, email))
+
+	_, err = g.Login(ctx, email, password, models.Client{IP: "10.0.0.4"})
+	assert.NoError(t, err)
+}
+
+func TestLoginGuardUsecase_Backoff(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	repo := memory.NewLoginAttemptRepository(db)
+	u := newUserUsecase(db)
+	g := newLoginGuard(db, u)
+
+	_, email, password := createUser(t, u)
+	key := "account:" + strings.ToLower(email)
+
+	for i := 1; i < lockoutOptions.MaxAttempts; i++ {
+		_, err := g.Login(ctx, email, "wrong"+password, models.Client{})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+	}
+
+	// Every failure after a lockout ends doubles the next one, up to the maximum.
+	for _, lockout := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		_, err := g.Login(ctx, email, "wrong"+password, models.Client{})
+		assertLockedOut(t, err, lockout)
+
+		require.NoError(t, repo.LockLogin(ctx, key, time.Now().Add(-time.Second)))
+	}
+}
+
+func TestLoginGuardUsecase_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	g := newLoginGuard(db, u)
+
+	_, email, password := createUser(t, u)
+
+	// Guesses sent at once get no more password checks than the limit.
+	const guesses = 20
+	var wg sync.WaitGroup
+	var invalid, lockedOut atomic.Int32
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := g.Login(ctx, email, "wrong"+password, models.Client{IP: "10.0.0." + strconv.Itoa(i)})
+			switch {
+			case errors.Is(err, usecase.ErrTooManyAttempts):
+				lockedOut.Add(1)
+			case errors.Is(err, usecase.ErrInvalidCredentials):
+				invalid.Add(1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The last checked guess answers with the lockout it caused.
+	assert.Equal(t, int32(lockoutOptions.MaxAttempts-1), invalid.Load())
+	assert.Equal(t, int32(guesses-lockoutOptions.MaxAttempts+1), lockedOut.Load())
+
+	_, err := g.Login(ctx, email, password, models.Client{IP: "10.0.1.1"})
+	assert.ErrorIs(t, err, usecase.ErrTooManyAttempts)
+}
+
+func TestLoginGuardUsecase_IP(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	g := newLoginGuard(db, u)
+
+	_, email, password := createUser(t, u)
+
+	// Guessing a different account every time only trips the address limit.
+	for i := 1; i < lockoutOptions.MaxIPAttempts; i++ {
+		_, err := g.Login(ctx, strings.Repeat("x", i)+email, password, models.Client{IP: "10.0.0.1"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+	}
+
+	// A valid login from the address doesn't reset its count.
+	_, err := g.Login(ctx, email, password, models.Client{IP: "10.0.0.1"})
+	require.NoError(t, err)
+
+	_, err = g.Login(ctx, "other"+email, password, models.Client{IP: "10.0.0.1"})
+	assertLockedOut(t, err, time.Minute)
+
+	_, err = g.Login(ctx, email, password, models.Client{IP: "10.0.0.1"})
+	assertLockedOut(t, err, time.Minute)
+
+	_, err = g.Login(ctx, email, password, models.Client{IP: "10.0.0.2"})
+	assert.NoError(t, err)
+}
+
+func TestLoginGuardUsecase_PurgeExpiredAttempts(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	repo := memory.NewLoginAttemptRepository(db)
+	g := newLoginGuard(db, newUserUsecase(db))
+
+	now := time.Now()
+	_, _, err := repo.ReserveLoginAttempt(ctx, "ip:old", now.Add(-time.Hour), now.Add(-2*time.Hour), 5, now)
+	require.NoError(t, err)
+	_, _, err = repo.ReserveLoginAttempt(ctx, "ip:recent", now, now.Add(-time.Hour), 5, now)
+	require.NoError(t, err)
+
+	require.NoError(t, g.PurgeExpiredAttempts(ctx))
+
+	old, err := repo.LoginAttempts(ctx, "ip:old")
+	require.NoError(t, err)
+	assert.Zero(t, old.Failures)
+
+	recent, err := repo.LoginAttempts(ctx, "ip:recent")
+	require.NoError(t, err)
+	assert.Equal(t, 1, recent.Failures)
+}
+
+func TestLoginGuardUsecase_SecondFactor(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	g := newLoginGuard(db, u)
+
+	userID, email, password := createUser(t, u)
+
+	setup, err := u.SetupTwoFactor(ctx, userID)
+	require.NoError(t, err)
+	_, err = u.ConfirmTwoFactor(ctx, userID, totpCode(t, setup.Secret, 0))
+	require.NoError(t, err)
+
+	tokens, err := g.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens.MFA)
+
+	_, err = g.LoginTwoFactor(ctx, "garbage", "000000", testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	for i := 1; i < lockoutOptions.MaxAttempts; i++ {
+		_, err := g.LoginTwoFactor(ctx, tokens.MFA, "000000", testClient)
+		assert.ErrorIs(t, err, usecase.ErrInvalidCode)
+	}
+
+	_, err = g.LoginTwoFactor(ctx, tokens.MFA, "000000", testClient)
+	assertLockedOut(t, err, time.Minute)
+
+	// A new mfa token doesn't give new guesses, not even the right one.
+	tokens, err = g.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	_, err = g.LoginTwoFactor(ctx, tokens.MFA, totpCode(t, setup.Secret, 1), testClient)
+	assertLockedOut(t, err, time.Minute)
+
+	require.NoError(t, memory.NewLoginAttemptRepository(db).ResetLoginAttempts(ctx, "2fa:"+strconv.FormatInt(userID, 10)))
+
+	// Surrounding spaces are ignored.
+	pair, err := g.LoginTwoFactor(ctx, tokens.MFA, " "+totpCode(t, setup.Secret, 1)+" ", testClient)
+	require.NoError(t, err)
+	assert.NotEmpty(t, pair.Access)
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/oidc"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrProviderNotFound    = errors.New("identity provider not found")
+	ErrExternalLoginFailed = errors.New("external login failed")
+)
+
+type oidcProvider interface {
+	AuthURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, nonce, verifier string) (oidc.Identity, error)
+}
+
+type identityRepository interface {
+	SaveIdentity(ctx context.Context, identity *models.UserIdentity) error
+	Identity(ctx context.Context, provider, subject string) (models.UserIdentity, error)
+}
+
+type oidcStateManager interface {
+	CreateStateToken(state models.OIDCState) (string, error)
+	ParseStateToken(tokenStr string) (models.OIDCState, error)
+}
+
+type tokenIssuer interface {
+	IssueTokens(ctx context.Context, user *models.User, client models.Client) (models.Tokens, error)
+}
+
+// OIDCUsecase logs users in with external OpenID providers. The first login with a provider
+// links the identity to the account with the same verified email or creates a new account.
+type OIDCUsecase struct {
+	providers          map[string]oidcProvider
+	passwordHasher     passwordHasher
+	userRepository     userRepository
+	identityRepository identityRepository
+	states             oidcStateManager
+	tokenIssuer        tokenIssuer
+	logger             *slog.Logger
+}
+
+func NewOIDCUsecase(
+	passwordHasher passwordHasher,
+	userRepository userRepository,
+	identityRepository identityRepository,
+	states oidcStateManager,
+	tokenIssuer tokenIssuer,
+	logger *slog.Logger,
+) *OIDCUsecase {
+	return &OIDCUsecase{
+		providers:          make(map[string]oidcProvider),
+		passwordHasher:     passwordHasher,
+		userRepository:     userRepository,
+		identityRepository: identityRepository,
+		states:             states,
+		tokenIssuer:        tokenIssuer,
+		logger:             logger,
+	}
+}
+
+// AddProvider makes the provider available under the name. It must be called before serving requests.
+func (u *OIDCUsecase) AddProvider(name string, provider oidcProvider) {
+	u.providers[name] = provider
+}
+
+// StartOIDC begins a login with the provider. It returns the URL to redirect the user to
+// and the state token the user agent has to keep until the callback.
+func (u *OIDCUsecase) StartOIDC(ctx context.Context, provider string) (string, string, error) {
+	const op = "usecase.StartOIDC"
+
+	log := u.logger.With(slog.String("op", op), slog.String("provider", provider))
+
+	p, ok := u.providers[provider]
+	if !ok {
+		log.Info("unknown provider")
+
+		return "", "", fmt.Errorf("%s: %w", op, ErrProviderNotFound)
+	}
+
+	state := models.OIDCState{Provider: provider}
+	for _, v := range []*string{&state.State, &state.Nonce, &state.Verifier} {
+		token, err := oidc.RandomToken()
+		if err != nil {
+			log.Error("failed to generate state", sl.Err(err))
+
+			return "", "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		*v = token
+	}
+
+	authURL, err := p.AuthURL(ctx, state.State, state.Nonce, state.Verifier)
+	if err != nil {
+		log.Error("failed to build authorization url", sl.Err(err))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	stateToken, err := u.states.CreateStateToken(state)
+	if err != nil {
+		log.Error("failed to create state token", sl.Err(err))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return authURL, stateToken, nil
+}
+
+// FinishOIDC completes the login on the callback from the provider. The state must match
+// the state token created by StartOIDC. Returns the tokens of the user like Login does.
+func (u *OIDCUsecase) FinishOIDC(
+	ctx context.Context,
+	provider, stateToken, state, code string,
+	client models.Client,
+) (models.Tokens, error) {
+	const op = "usecase.FinishOIDC"
+
+	log := u.logger.With(slog.String("op", op), slog.String("provider", provider))
+
+	p, ok := u.providers[provider]
+	if !ok {
+		log.Info("unknown provider")
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrProviderNotFound)
+	}
+
+	expected, err := u.states.ParseStateToken(stateToken)
+	if err != nil {
+		log.Info("invalid state token", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if expected.Provider != provider || subtle.ConstantTimeCompare([]byte(expected.State), []byte(state)) != 1 {
+		log.Info("state mismatch")
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	identity, err := p.Exchange(ctx, code, expected.Nonce, expected.Verifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrRejected) || errors.Is(err, oidc.ErrInvalidIDToken) {
+			log.Info("provider login failed", sl.Err(err))
+
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrExternalLoginFailed)
+		}
+
+		log.Error("failed to exchange authorization code", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("subject", identity.Subject))
+
+	user, err := u.identityUser(ctx, log, provider, identity)
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := u.tokenIssuer.IssueTokens(ctx, &user, client)
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in with external provider", slog.Int64("user_id", user.ID))
+
+	return tokens, nil
+}
+
+// identityUser returns the user the identity is linked to, linking it on first login.
+// Only emails verified by both sides are trusted: otherwise whoever registered the
+// address first could take over the account of its real owner.
+func (u *OIDCUsecase) identityUser(
+	ctx context.Context,
+	log *slog.Logger,
+	provider string,
+	identity oidc.Identity,
+) (models.User, error) {
+	linked, err := u.identityRepository.Identity(ctx, provider, identity.Subject)
+	if err == nil {
+		user, err := u.userRepository.UserByID(ctx, linked.UserID)
+		if err != nil {
+			log.Error("failed to get linked user", sl.Err(err))
+
+			return models.User{}, err
+		}
+
+		return user, nil
+	}
+	if !errors.Is(err, storage.ErrIdentityNotFound) {
+		log.Error("failed to get identity", sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		log.Info("provider didn't verify the email")
+
+		return models.User{}, ErrEmailNotVerified
+	}
+
+	user, err := u.userRepository.User(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if user.VerifiedAt == nil {
+			log.Info("refusing to link account with unverified email", slog.Int64("user_id", user.ID))
+
+			return models.User{}, ErrUserAlreadyExists
+		}
+	case errors.Is(err, storage.ErrUserNotFound):
+		user, err = u.createUser(ctx, identity)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserAlreadyExists) {
+				log.Info("user already exists")
+
+				return models.User{}, ErrUserAlreadyExists
+			}
+
+			log.Error("failed to create user", sl.Err(err))
+
+			return models.User{}, err
+		}
+
+		log.Info("user created", slog.Int64("user_id", user.ID))
+	default:
+		log.Error("failed to get user from repository", sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	err = u.identityRepository.SaveIdentity(ctx, &models.UserIdentity{
+		Provider: provider,
+		Subject:  identity.Subject,
+		UserID:   user.ID,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		log.Error("failed to link identity", sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	log.Info("identity linked", slog.Int64("user_id", user.ID))
+
+	return user, nil
+}
+
+// createUser registers the user of the identity with a verified email and a random password.
+// The user can set a password with a password reset.
+func (u *OIDCUsecase) createUser(ctx context.Context, identity oidc.Identity) (models.User, error) {
+	password, _, err := tokenutil.NewOpaqueToken()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	hash, err := u.passwordHasher.Generate(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user := models.User{
+		HashedPassword: hash,
+		Email:          identity.Email,
+		Name:           identity.Name,
+		Locale:         mailer.DefaultLocale,
+	}
+
+	user.ID, err = u.userRepository.SaveUser(ctx, &user)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	now := time.Now().UTC()
+	if err := u.userRepository.VerifyUser(ctx, user.ID, user.Email, now); err != nil {
+		return models.User{}, err
+	}
+	user.VerifiedAt = &now
+
+	return user, nil
+}
@@ -0,0 +1,179 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/oidc"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/oidc/oidctest"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const oidcProvider = "corp"
+
+func newOIDCUsecase(t *testing.T, db *memory.DB, u *usecase.UserUsecase) (*usecase.OIDCUsecase, *oidctest.Server) {
+	t.Helper()
+
+	srv, err := oidctest.NewServer("tracker", "secret")
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+
+	o := usecase.NewOIDCUsecase(
+		password_hasher.NewBcryptPasswordHasher(),
+		memory.NewUserRepository(db),
+		memory.NewIdentityRepository(db),
+		tokenutil.NewOIDCStateManager("test_oidc_secret", time.Minute),
+		u,
+		newLogger(),
+	)
+	o.AddProvider(oidcProvider, oidc.NewProvider(oidc.Config{
+		Issuer:       srv.Issuer(),
+		ClientID:     srv.ClientID,
+		ClientSecret: srv.ClientSecret,
+		RedirectURL:  "https://tracker.example.com/api/auth/oidc/corp/callback",
+	}, srv.Client()))
+
+	return o, srv
+}
+
+// oidcLogin signs the current user of srv in through the whole flow.
+func oidcLogin(t *testing.T, o *usecase.OIDCUsecase, srv *oidctest.Server) (string, error) {
+	t.Helper()
+
+	authURL, stateToken, err := o.StartOIDC(context.Background(), oidcProvider)
+	require.NoError(t, err)
+
+	callback, err := srv.Authorize(authURL)
+	require.NoError(t, err)
+
+	q := callback.Query()
+	tokens, err := o.FinishOIDC(context.Background(), oidcProvider, stateToken, q.Get("state"), q.Get("code"), testClient)
+
+	return tokens.Access, err
+}
+
+func TestOIDCUsecase_NewUser(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	o, srv := newOIDCUsecase(t, db, u)
+
+	srv.SetUser(oidctest.User{Subject: "s-1", Email: "jane@example.com", EmailVerified: true, Name: "Jane"})
+
+	access, err := oidcLogin(t, o, srv)
+	require.NoError(t, err)
+
+	claims, err := u.Authenticate(ctx, access)
+	require.NoError(t, err)
+
+	user, err := u.Profile(ctx, claims.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", user.Email)
+	assert.Equal(t, "Jane", user.Name)
+	assert.NotNil(t, user.VerifiedAt)
+
+	// The subject identifies the user even after the email changes at the provider.
+	srv.SetUser(oidctest.User{Subject: "s-1", Email: "jane@corp.example.com", EmailVerified: true})
+
+	access, err = oidcLogin(t, o, srv)
+	require.NoError(t, err)
+
+	again, err := u.Authenticate(ctx, access)
+	require.NoError(t, err)
+	assert.Equal(t, claims.UserID, again.UserID)
+}
+
+func TestOIDCUsecase_LinkExisting(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	box := &mailbox{}
+	u := newUserUsecaseWithMail(db, box, usecase.VerificationOptions{URL: "https://tracker.example.com/verify"})
+	o, srv := newOIDCUsecase(t, db, u)
+
+	userID, email, _ := createUser(t, u)
+
+	srv.SetUser(oidctest.User{Subject: "s-2", Email: email, EmailVerified: false})
+
+	_, err := oidcLogin(t, o, srv)
+	assert.ErrorIs(t, err, usecase.ErrEmailNotVerified)
+
+	// The local account isn't verified yet, it could belong to someone else.
+	srv.SetUser(oidctest.User{Subject: "s-2", Email: email, EmailVerified: true})
+
+	_, err = oidcLogin(t, o, srv)
+	assert.ErrorIs(t, err, usecase.ErrUserAlreadyExists)
+
+	require.NoError(t, u.VerifyEmail(ctx, linkToken(t, box.last(t).Text)))
+
+	access, err := oidcLogin(t, o, srv)
+	require.NoError(t, err)
+
+	claims, err := u.Authenticate(ctx, access)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+}
+
+func TestOIDCUsecase_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	o, srv := newOIDCUsecase(t, db, u)
+
+	_, err := oidcLogin(t, o, srv)
+	require.NoError(t, err)
+
+	user, err := memory.NewUserRepository(db).User(ctx, "user@example.com")
+	require.NoError(t, err)
+
+	setup, err := u.SetupTwoFactor(ctx, user.ID)
+	require.NoError(t, err)
+	_, err = u.ConfirmTwoFactor(ctx, user.ID, totpCode(t, setup.Secret, 0))
+	require.NoError(t, err)
+
+	authURL, stateToken, err := o.StartOIDC(ctx, oidcProvider)
+	require.NoError(t, err)
+	callback, err := srv.Authorize(authURL)
+	require.NoError(t, err)
+
+	tokens, err := o.FinishOIDC(ctx, oidcProvider, stateToken, callback.Query().Get("state"), callback.Query().Get("code"), testClient)
+	require.NoError(t, err)
+	assert.Empty(t, tokens.Access)
+	assert.NotEmpty(t, tokens.MFA)
+}
+
+func TestOIDCUsecase_InvalidCallback(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	o, srv := newOIDCUsecase(t, db, newUserUsecase(db))
+
+	_, _, err := o.StartOIDC(ctx, "unknown")
+	assert.ErrorIs(t, err, usecase.ErrProviderNotFound)
+
+	authURL, stateToken, err := o.StartOIDC(ctx, oidcProvider)
+	require.NoError(t, err)
+	callback, err := srv.Authorize(authURL)
+	require.NoError(t, err)
+
+	state, code := callback.Query().Get("state"), callback.Query().Get("code")
+
+	_, err = o.FinishOIDC(ctx, oidcProvider, stateToken, "forged", code, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = o.FinishOIDC(ctx, oidcProvider, "garbage", state, code, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = o.FinishOIDC(ctx, "other", stateToken, state, code, testClient)
+	assert.ErrorIs(t, err, usecase.ErrProviderNotFound)
+
+	_, err = o.FinishOIDC(ctx, oidcProvider, stateToken, state, code, testClient)
+	require.NoError(t, err)
+
+	// A replayed callback is refused by the provider, codes are single use.
+	_, err = o.FinishOIDC(ctx, oidcProvider, stateToken, state, code, testClient)
+	assert.ErrorIs(t, err, usecase.ErrExternalLoginFailed)
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+	"time"
+)
+
+type passwordResetRepository interface {
+	SaveResetToken(ctx context.Context, userID int64, tokenHash string, expiry time.Time) error
+	ConsumeResetToken(ctx context.Context, tokenHash string, now time.Time) (int64, error)
+	DeleteUserResetTokens(ctx context.Context, userID int64) error
+	DeleteExpiredResetTokens(ctx context.Context, before time.Time) (int64, error)
+}
+
+type accountUnlocker interface {
+	UnlockAccount(ctx context.Context, email string) error
+}
+
+// PasswordResetOptions configure password reset emails.
+type PasswordResetOptions struct {
+	// URL is the page reset links point to. The token is passed in the token query parameter.
+	// If it is empty, emails contain the bare token.
+	URL string
+	// TokenTTL is how long a reset token can be used.
+	TokenTTL time.Duration
+}
+
+type PasswordResetUsecase struct {
+	passwordHasher          passwordHasher
+	userRepository          userRepository
+	passwordResetRepository passwordResetRepository
+	tokenRepository         tokenRepository
+	accountUnlocker         accountUnlocker
+	mailSender              mailSender
+	options                 PasswordResetOptions
+	logger                  *slog.Logger
+}
+
+func NewPasswordResetUsecase(
+	passwordHasher passwordHasher,
+	userRepository userRepository,
+	passwordResetRepository passwordResetRepository,
+	tokenRepository tokenRepository,
+	accountUnlocker accountUnlocker,
+	mailSender mailSender,
+	options PasswordResetOptions,
+	logger *slog.Logger,
+) *PasswordResetUsecase {
+	return &PasswordResetUsecase{
+		passwordHasher:          passwordHasher,
+		userRepository:          userRepository,
+		passwordResetRepository: passwordResetRepository,
+		tokenRepository:         tokenRepository,
+		accountUnlocker:         accountUnlocker,
+		mailSender:              mailSender,
+		options:                 options,
+		logger:                  logger,
+	}
+}
+
+// ForgotPassword emails a one-time password reset token if the account exists.
+// It doesn't report whether the account exists, so once the account is found
+// failures to issue or send the token are only logged.
+func (u *PasswordResetUsecase) ForgotPassword(ctx context.Context, email string) error {
+	const op = "usecase.ForgotPassword"
+
+	log := u.logger.With(slog.String("op", op))
+
+	user, err := u.userRepository.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+
+			return nil
+		}
+
+		log.Error("failed to get user from repository", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", user.ID))
+
+	if err := u.sendResetToken(ctx, &user); err != nil {
+		log.Error("failed to send password reset email", sl.Err(err))
+
+		return nil
+	}
+
+	log.Info("password reset requested")
+
+	return nil
+}
+
+func (u *PasswordResetUsecase) sendResetToken(ctx context.Context, user *models.User) error {
+	token, tokenHash, err := tokenutil.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	err = u.passwordResetRepository.SaveResetToken(ctx, user.ID, tokenHash, time.Now().Add(u.options.TokenTTL))
+	if err != nil {
+		return err
+	}
+
+	link, err := tokenLink(u.options.URL, token)
+	if err != nil {
+		return err
+	}
+
+	return u.mailSender.SendTemplate(ctx, user.Email, user.Locale, mailer.TemplatePasswordReset, mailer.TokenData{
+		Name:  user.Name,
+		Token: token,
+		Link:  link,
+	})
+}
+
+// ResetPassword sets a new password of the user the reset token was issued for.
+// The token can be used once. On success every other reset token of the user
+// is dropped, all of their sessions are logged out and the account is unlocked
+// if too many failed logins locked it out.
+func (u *PasswordResetUsecase) ResetPassword(ctx context.Context, token, password string) error {
+	const op = "usecase.ResetPassword"
+
+	log := u.logger.With(slog.String("op", op))
+
+	userID, err := u.passwordResetRepository.ConsumeResetToken(ctx, tokenutil.HashOpaqueToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, storage.ErrResetTokenNotFound) {
+			log.Info("reset token not found")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to consume reset token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", userID))
+
+	hashedPassword, err := u.passwordHasher.Generate(password)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		log.Error("failed to update password", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.passwordResetRepository.DeleteUserResetTokens(ctx, userID); err != nil {
+		log.Error("failed to delete reset tokens", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.tokenRepository.RevokeUserFamilies(ctx, userID); err != nil {
+		log.Error("failed to revoke token families", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := u.userRepository.UserByID(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user from repository", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.accountUnlocker.UnlockAccount(ctx, user.Email); err != nil {
+		log.Error("failed to unlock account", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset")
+
+	return nil
+}
+
+// PurgeExpiredTokens removes reset tokens that can't be used anymore.
+func (u *PasswordResetUsecase) PurgeExpiredTokens(ctx context.Context) error {
+	const op = "usecase.PasswordResetUsecase.PurgeExpiredTokens"
+
+	log := u.logger.With(slog.String("op", op))
+
+	n, err := u.passwordResetRepository.DeleteExpiredResetTokens(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to purge expired reset tokens", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Debug("expired reset tokens purged", slog.Int64("count", n))
+
+	return nil
+}
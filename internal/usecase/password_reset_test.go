@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newPasswordResetUsecase(db *memory.DB, box *mailbox, ttl time.Duration) *usecase.PasswordResetUsecase {
+	return usecase.NewPasswordResetUsecase(
+		password_hasher.NewBcryptPasswordHasher(),
+		memory.NewUserRepository(db),
+		memory.NewPasswordResetRepository(db),
+		memory.NewTokenRepository(db),
+		newLoginGuard(db, newUserUsecase(db)),
+		newMailSender(box),
+		usecase.PasswordResetOptions{
+			URL:      "https://tracker.example.com/reset-password",
+			TokenTTL: ttl,
+		},
+		newLogger(),
+	)
+}
+
+func TestPasswordResetUsecase_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	box := &mailbox{}
+	u := newUserUsecase(db)
+	r := newPasswordResetUsecase(db, box, time.Hour)
+
+	_, email, password := createUser(t, u)
+
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	require.NoError(t, r.ForgotPassword(ctx, email))
+
+	msg := box.last(t)
+	assert.Equal(t, email, msg.To)
+
+	token := linkToken(t, msg.Text)
+	newPassword := "new" + password
+
+	require.NoError(t, r.ResetPassword(ctx, token, newPassword))
+
+	_, err = u.Login(ctx, email, password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	_, err = u.Login(ctx, email, newPassword, testClient)
+	assert.NoError(t, err)
+
+	// Sessions started with the old password are logged out.
+	_, err = u.Refresh(ctx, tokens.Refresh, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	// Tokens are single-use.
+	err = r.ResetPassword(ctx, token, newPassword)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	err = r.ResetPassword(ctx, "garbage", newPassword)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
+
+func TestPasswordResetUsecase_ResetPassword_InvalidatesOtherTokens(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	box := &mailbox{}
+	u := newUserUsecase(db)
+	r := newPasswordResetUsecase(db, box, time.Hour)
+
+	_, email, password := createUser(t, u)
+
+	require.NoError(t, r.ForgotPassword(ctx, email))
+	first := linkToken(t, box.last(t).Text)
+
+	require.NoError(t, r.ForgotPassword(ctx, email))
+	second := linkToken(t, box.last(t).Text)
+
+	require.NoError(t, r.ResetPassword(ctx, second, "new"+password))
+
+	err := r.ResetPassword(ctx, first, "other"+password)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
+
+func TestPasswordResetUsecase_ResetPassword_Expired(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	box := &mailbox{}
+	u := newUserUsecase(db)
+	r := newPasswordResetUsecase(db, box, -time.Minute)
+
+	_, email, password := createUser(t, u)
+
+	require.NoError(t, r.ForgotPassword(ctx, email))
+
+	err := r.ResetPassword(ctx, linkToken(t, box.last(t).Text), "new"+password)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	require.NoError(t, r.PurgeExpiredTokens(ctx))
+}
+
+func TestPasswordResetUsecase_ForgotPassword_UnknownEmail(t *testing.T) {
+	box := &mailbox{}
+	r := newPasswordResetUsecase(memory.NewDB(), box, time.Hour)
+
+	require.NoError(t, r.ForgotPassword(context.Background(), "unknown@example.com"))
+	assert.Empty(t, box.messages)
+}
+
+func TestPasswordResetUsecase_ForgotPassword_SendFailure(t *testing.T) {
+	db := memory.NewDB()
+	box := &mailbox{err: errors.New("smtp is down")}
+	r := newPasswordResetUsecase(db, box, time.Hour)
+
+	_, email, _ := createUser(t, newUserUsecase(db))
+
+	// The failure isn't reported, it would tell that the account exists.
+	require.NoError(t, r.ForgotPassword(context.Background(), email))
+	assert.Empty(t, box.messages)
+}
+
+func TestPasswordResetUsecase_ResetPassword_UnlocksAccount(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	box := &mailbox{}
+	u := newUserUsecase(db)
+	g := newLoginGuard(db, u)
+	r := newPasswordResetUsecase(db, box, time.Hour)
+
+	_, email, password := createUser(t, u)
+
+	var err error
+	for i := 0; i < lockoutOptions.MaxAttempts; i++ {
+		_, err = g.Login(ctx, email, "wrong"+password, models.Client{})
+	}
+	assert.ErrorIs(t, err, usecase.ErrTooManyAttempts)
+
+	require.NoError(t, r.ForgotPassword(ctx, email))
+	require.NoError(t, r.ResetPassword(ctx, linkToken(t, box.last(t).Text), "new"+password))
+
+	_, err = g.Login(ctx, email, "new"+password, models.Client{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+)
+
+// Profile returns the user.
+func (u *UserUsecase) Profile(ctx context.Context, userID int64) (models.User, error) {
+	const op = "usecase.Profile"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	user, err := u.userRepository.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user from repository", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// UpdateProfile changes the fields of upd and returns the updated user.
+// A new email address has to be verified again, a verification email is sent to it.
+func (u *UserUsecase) UpdateProfile(ctx context.Context, userID int64, upd models.UserUpdate) (models.User, error) {
+	const op = "usecase.UpdateProfile"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	user, err := u.Profile(ctx, userID)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	emailChanged := upd.Email != nil && *upd.Email != user.Email
+
+	if emailChanged {
+		user.Email = *upd.Email
+		user.VerifiedAt = nil
+	}
+	if upd.Name != nil {
+		user.Name = *upd.Name
+	}
+	if upd.Locale != nil {
+		user.Locale = *upd.Locale
+	}
+
+	if err := u.userRepository.UpdateUser(ctx, &user); err != nil {
+		if errors.Is(err, storage.ErrUserAlreadyExists) {
+			log.Info("email is taken")
+
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserAlreadyExists)
+		}
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to update user", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// As on registration, the change is kept even if the email can't be sent.
+	if emailChanged {
+		if err := u.sendVerification(ctx, &user); err != nil {
+			log.Error("failed to send verification email", sl.Err(err))
+		}
+	}
+
+	log.Info("profile updated")
+
+	return user, nil
+}
+
+// ChangePassword replaces the password of the user after checking the current one.
+// Every session of the user is logged out and a new token pair is returned for the caller.
+func (u *UserUsecase) ChangePassword(
+	ctx context.Context,
+	userID int64,
+	currentPassword, newPassword string,
+	client models.Client,
+) (models.Tokens, error) {
+	const op = "usecase.ChangePassword"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	user, err := u.Profile(ctx, userID)
+	if err != nil {
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.passwordHasher.Compare(user.HashedPassword, currentPassword); err != nil {
+		log.Info("incorrect password")
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	hashedPassword, err := u.passwordHasher.Generate(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		log.Error("failed to update password", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.tokenRepository.RevokeUserFamilies(ctx, userID); err != nil {
+		log.Error("failed to revoke token families", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := u.getTokens(ctx, &user, client)
+	if err != nil {
+		log.Error("failed to create user tokens", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password changed")
+
+	return tokens, nil
+}
+
+// DeleteAccount deletes the user with all of their applications, phases and tokens.
+// Tokens issued before stop working since their families are gone.
+func (u *UserUsecase) DeleteAccount(ctx context.Context, userID int64) error {
+	const op = "usecase.DeleteAccount"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	if err := u.userRepository.DeleteUser(ctx, userID); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to delete user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("account deleted")
+
+	return nil
+}
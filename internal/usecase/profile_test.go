@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUserUsecase_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	box := &mailbox{}
+	u := newUserUsecaseWithMail(memory.NewDB(), box, usecase.VerificationOptions{
+		URL: "https://tracker.example.com/verify",
+	})
+
+	userID, email, _ := createUser(t, u)
+	require.NoError(t, u.VerifyEmail(ctx, linkToken(t, box.last(t).Text)))
+
+	name, locale := "Jane", "ru"
+
+	user, err := u.UpdateProfile(ctx, userID, models.UserUpdate{Name: &name, Locale: &locale})
+	require.NoError(t, err)
+	assert.Equal(t, name, user.Name)
+	assert.Equal(t, locale, user.Locale)
+	assert.Equal(t, email, user.Email)
+	assert.NotNil(t, user.VerifiedAt)
+	require.Len(t, box.messages, 1)
+
+	// A new email has to be verified again.
+	newEmail := "new" + email
+
+	user, err = u.UpdateProfile(ctx, userID, models.UserUpdate{Email: &newEmail})
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, user.Email)
+	assert.Nil(t, user.VerifiedAt)
+	assert.Equal(t, name, user.Name)
+
+	require.Len(t, box.messages, 2)
+	assert.Equal(t, newEmail, box.last(t).To)
+
+	got, err := u.Profile(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, user, got)
+
+	_, otherEmail, _ := createUser(t, u)
+
+	_, err = u.UpdateProfile(ctx, userID, models.UserUpdate{Email: &otherEmail})
+	assert.ErrorIs(t, err, usecase.ErrUserAlreadyExists)
+}
+
+func TestUserUsecase_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	u := newUserUsecase(memory.NewDB())
+
+	userID, email, password := createUser(t, u)
+
+	old, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	_, err = u.ChangePassword(ctx, userID, "wrong"+password, "new"+password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	tokens, err := u.ChangePassword(ctx, userID, password, "new"+password, testClient)
+	require.NoError(t, err)
+
+	// Other sessions are logged out, the returned tokens work.
+	_, err = u.Authenticate(ctx, old.Access)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = u.Authenticate(ctx, tokens.Access)
+	assert.NoError(t, err)
+
+	_, err = u.Login(ctx, email, password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	_, err = u.Login(ctx, email, "new"+password, testClient)
+	assert.NoError(t, err)
+}
+
+func TestUserUsecase_DeleteAccount(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	apps := usecase.NewApplicationUsecase(memory.NewApplicationRepository(db), newLogger())
+
+	userID, email, password := createUser(t, u)
+
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	appID, err := apps.CreateApplication(ctx, userID, fakeApplication())
+	require.NoError(t, err)
+
+	require.NoError(t, u.DeleteAccount(ctx, userID))
+
+	_, err = u.Profile(ctx, userID)
+	assert.ErrorIs(t, err, usecase.ErrUserNotFound)
+
+	_, err = apps.Application(ctx, userID, appID)
+	assert.ErrorIs(t, err, usecase.ErrApplicationNotFound)
+
+	_, err = u.Authenticate(ctx, tokens.Access)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = u.Login(ctx, email, password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	err = u.DeleteAccount(ctx, userID)
+	assert.ErrorIs(t, err, usecase.ErrUserNotFound)
+}
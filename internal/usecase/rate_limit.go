@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type rateLimitRepository interface {
+	TakeToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (models.RateLimitResult, error)
+	DeleteIdleBuckets(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RateLimitRule limits the requests to a route. Route is a path, optionally preceded
+// by a method, such as "POST /api/auth/login". It matches the path and the paths below it.
+type RateLimitRule struct {
+	Route string
+	Limit models.RateLimit
+}
+
+// RateLimitUsecase limits the requests of every client per route with token buckets.
+// A request counts against the most specific rule matching it only.
+type RateLimitUsecase struct {
+	rateLimitRepository rateLimitRepository
+	rules               []RateLimitRule
+	logger              *slog.Logger
+}
+
+func NewRateLimitUsecase(rateLimitRepository rateLimitRepository, rules []RateLimitRule, logger *slog.Logger) *RateLimitUsecase {
+	return &RateLimitUsecase{
+		rateLimitRepository: rateLimitRepository,
+		rules:               rules,
+		logger:              logger,
+	}
+}
+
+// Allow takes a token from the bucket of the client for the route of the request.
+// Requests not matching any rule are allowed with a zero Limit.
+func (u *RateLimitUsecase) Allow(ctx context.Context, client, method, path string) (models.RateLimitResult, error) {
+	const op = "usecase.RateLimit.Allow"
+
+	rule, ok := u.rule(method, path)
+	if !ok {
+		return models.RateLimitResult{Allowed: true}, nil
+	}
+
+	res, err := u.rateLimitRepository.TakeToken(ctx, rule.Route+"|"+client, rule.Limit, time.Now())
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// PurgeIdleBuckets removes buckets that had time to fill up, they are the same as new ones.
+func (u *RateLimitUsecase) PurgeIdleBuckets(ctx context.Context) error {
+	const op = "usecase.RateLimit.PurgeIdleBuckets"
+
+	log := u.logger.With(slog.String("op", op))
+
+	var fill time.Duration
+	for _, rule := range u.rules {
+		l := rule.Limit
+		fill = max(fill, l.Period*time.Duration(l.Capacity())/time.Duration(l.Requests))
+	}
+
+	n, err := u.rateLimitRepository.DeleteIdleBuckets(ctx, time.Now().Add(-fill))
+	if err != nil {
+		log.Error("failed to purge idle rate limit buckets", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Debug("idle rate limit buckets purged", slog.Int64("count", n))
+
+	return nil
+}
+
+// rule returns the rule with the longest route matching the request.
+// A rule with a method wins over one without for the same path.
+func (u *RateLimitUsecase) rule(method, path string) (RateLimitRule, bool) {
+	var (
+		best      RateLimitRule
+		bestScore = -1
+	)
+
+	for _, rule := range u.rules {
+		ruleMethod, rulePath, ok := strings.Cut(rule.Route, " ")
+		if !ok {
+			ruleMethod, rulePath = "", rule.Route
+		}
+
+		if ruleMethod != "" && !strings.EqualFold(ruleMethod, method) {
+			continue
+		}
+
+		if !matchPath(rulePath, path) {
+			continue
+		}
+
+		score := 2 * len(strings.TrimSuffix(rulePath, "/"))
+		if ruleMethod != "" {
+			score++
+		}
+
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+
+	return best, bestScore >= 0
+}
+
+// matchPath reports whether path is the route or below it.
+func matchPath(route, path string) bool {
+	route = strings.TrimSuffix(route, "/")
+
+	return path == route || strings.HasPrefix(path, route+"/")
+}
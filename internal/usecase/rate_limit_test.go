@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRateLimitUsecase_Allow(t *testing.T) {
+	ctx := context.Background()
+	u := usecase.NewRateLimitUsecase(memory.NewRateLimitRepository(memory.NewDB()), []usecase.RateLimitRule{
+		{Route: "/", Limit: models.RateLimit{Requests: 100, Period: time.Minute}},
+		{Route: "/api/auth", Limit: models.RateLimit{Requests: 10, Period: time.Minute}},
+		{Route: "POST /api/auth/login", Limit: models.RateLimit{Requests: 2, Period: time.Minute}},
+	}, newLogger())
+
+	for i := 1; i >= 0; i-- {
+		res, err := u.Allow(ctx, "ip:10.0.0.1", "POST", "/api/auth/login")
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, err := u.Allow(ctx, "ip:10.0.0.1", "POST", "/api/auth/login")
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.InDelta(t, 30, res.RetryAfter.Seconds(), 1)
+	assert.InDelta(t, 60, res.Reset.Seconds(), 1)
+
+	// Other clients and routes have their own buckets.
+	res, err = u.Allow(ctx, "ip:10.0.0.2", "POST", "/api/auth/login")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = u.Allow(ctx, "ip:10.0.0.1", "POST", "/api/auth/register")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 10, res.Limit)
+
+	res, err = u.Allow(ctx, "ip:10.0.0.1", "GET", "/api/auth/login")
+	require.NoError(t, err)
+	assert.Equal(t, 10, res.Limit)
+
+	res, err = u.Allow(ctx, "user:1", "GET", "/api/authors")
+	require.NoError(t, err)
+	assert.Equal(t, 100, res.Limit)
+
+	// Without rules nothing is limited.
+	none := usecase.NewRateLimitUsecase(memory.NewRateLimitRepository(memory.NewDB()), nil, newLogger())
+	res, err = none.Allow(ctx, "ip:10.0.0.1", "GET", "/api/applications")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Zero(t, res.Limit)
+}
+
+func TestRateLimitRepository_Refill(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRateLimitRepository(memory.NewDB())
+	limit := models.RateLimit{Requests: 60, Period: time.Minute, Burst: 3}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		res, err := repo.TakeToken(ctx, "key", limit, now)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+
+	res, err := repo.TakeToken(ctx, "key", limit, now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	// A token a second comes back, up to the burst.
+	res, err = repo.TakeToken(ctx, "key", limit, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, err = repo.TakeToken(ctx, "key", limit, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+	assert.Equal(t, time.Second, res.Reset)
+
+	n, err := repo.DeleteIdleBuckets(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
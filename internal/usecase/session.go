@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type sessionRepository interface {
+	Sessions(ctx context.Context, userID int64, since time.Time) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID int64, id string) error
+	DeleteExpiredFamilies(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SessionUsecase lets users see the devices they are logged in on and log them out.
+// A session is the token family of a login, see UserUsecase.Refresh.
+type SessionUsecase struct {
+	sessionRepository sessionRepository
+	// ttl is the lifetime of refresh tokens: a session not refreshed for that long is over.
+	ttl    time.Duration
+	logger *slog.Logger
+}
+
+func NewSessionUsecase(sessionRepository sessionRepository, ttl time.Duration, logger *slog.Logger) *SessionUsecase {
+	return &SessionUsecase{
+		sessionRepository: sessionRepository,
+		ttl:               ttl,
+		logger:            logger,
+	}
+}
+
+// Sessions returns the active sessions of the user, most recently used first.
+// The session the claims belong to is marked as current.
+func (u *SessionUsecase) Sessions(ctx context.Context, claims models.TokenClaims) ([]models.Session, error) {
+	const op = "usecase.Sessions"
+
+	sessions, err := u.sessionRepository.Sessions(ctx, claims.UserID, time.Now().Add(-u.ttl))
+	if err != nil {
+		u.logger.Error("failed to get sessions", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == claims.FamilyID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession logs the user out of the session. Its refresh tokens can't be used
+// anymore and its access tokens are refused right away, like after Logout.
+func (u *SessionUsecase) RevokeSession(ctx context.Context, userID int64, id string) error {
+	const op = "usecase.RevokeSession"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID), slog.String("id", id))
+
+	if err := u.sessionRepository.RevokeSession(ctx, userID, id); err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			log.Info("session not found")
+
+			return fmt.Errorf("%s: %w", op, ErrSessionNotFound)
+		}
+
+		log.Error("failed to revoke session", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("session revoked")
+
+	return nil
+}
+
+// PurgeExpiredSessions removes the token families whose refresh tokens have all expired.
+// Their tokens can't pass validation anyway.
+func (u *SessionUsecase) PurgeExpiredSessions(ctx context.Context) error {
+	const op = "usecase.PurgeExpiredSessions"
+
+	log := u.logger.With(slog.String("op", op))
+
+	n, err := u.sessionRepository.DeleteExpiredFamilies(ctx, time.Now().Add(-u.ttl))
+	if err != nil {
+		log.Error("failed to purge expired sessions", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Debug("expired sessions purged", slog.Int64("count", n))
+
+	return nil
+}
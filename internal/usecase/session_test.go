@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSessionUsecase(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	s := usecase.NewSessionUsecase(memory.NewTokenRepository(db), refreshTTL, newLogger())
+
+	userID, email, password := createUser(t, u)
+
+	laptop := models.Client{UserAgent: "laptop", IP: "192.0.2.1"}
+	phone := models.Client{UserAgent: "phone", IP: "192.0.2.2"}
+
+	tokens, err := u.Login(ctx, email, password, laptop)
+	require.NoError(t, err)
+	other, err := u.Login(ctx, email, password, phone)
+	require.NoError(t, err)
+
+	claims, err := u.Authenticate(ctx, tokens.Access)
+	require.NoError(t, err)
+
+	sessions, err := s.Sessions(ctx, claims)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	for _, session := range sessions {
+		assert.Equal(t, userID, session.UserID)
+		assert.Equal(t, session.ID == claims.FamilyID, session.Current)
+		assert.False(t, session.LastUsedAt.Before(session.Created))
+	}
+
+	// Refreshing moves the session to the new address and to the top.
+	time.Sleep(time.Millisecond)
+	moved := models.Client{UserAgent: "laptop", IP: "198.51.100.7"}
+	tokens, err = u.Refresh(ctx, tokens.Refresh, moved)
+	require.NoError(t, err)
+
+	sessions, err = s.Sessions(ctx, claims)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, claims.FamilyID, sessions[0].ID)
+	assert.Equal(t, moved.IP, sessions[0].IP)
+	assert.True(t, sessions[0].LastUsedAt.After(sessions[0].Created))
+	assert.Equal(t, phone.UserAgent, sessions[1].UserAgent)
+	assert.False(t, sessions[1].Current)
+
+	otherID, _, _ := createUser(t, u)
+	assert.ErrorIs(t, s.RevokeSession(ctx, otherID, sessions[1].ID), usecase.ErrSessionNotFound)
+	assert.ErrorIs(t, s.RevokeSession(ctx, userID, "unknown"), usecase.ErrSessionNotFound)
+
+	require.NoError(t, s.RevokeSession(ctx, userID, sessions[1].ID))
+	assert.ErrorIs(t, s.RevokeSession(ctx, userID, sessions[1].ID), usecase.ErrSessionNotFound)
+
+	// The tokens of the revoked session stop working right away.
+	_, err = u.Authenticate(ctx, other.Access)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+	_, err = u.Refresh(ctx, other.Refresh, phone)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = u.Authenticate(ctx, tokens.Access)
+	assert.NoError(t, err)
+
+	sessions, err = s.Sessions(ctx, claims)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Current)
+}
+
+func TestSessionUsecase_PurgeExpiredSessions(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+	repo := memory.NewTokenRepository(db)
+
+	_, email, password := createUser(t, u)
+
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	claims, err := u.Authenticate(ctx, tokens.Access)
+	require.NoError(t, err)
+
+	s := usecase.NewSessionUsecase(repo, refreshTTL, newLogger())
+	require.NoError(t, s.PurgeExpiredSessions(ctx))
+
+	sessions, err := s.Sessions(ctx, claims)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	// A session not refreshed for longer than the lifetime of its tokens is over.
+	expired := usecase.NewSessionUsecase(repo, -time.Minute, newLogger())
+
+	sessions, err = expired.Sessions(ctx, claims)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	require.NoError(t, expired.PurgeExpiredSessions(ctx))
+
+	_, err = u.Refresh(ctx, tokens.Refresh, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/totp"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCode         = errors.New("invalid two-factor code")
+	ErrTwoFactorEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorDisabled   = errors.New("two-factor authentication is disabled")
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew is the number of time steps around the current one codes are accepted for.
+	totpSkew = 1
+)
+
+type twoFactorRepository interface {
+	SaveTOTP(ctx context.Context, userID int64, secret string) error
+	TOTP(ctx context.Context, userID int64) (models.TOTP, error)
+	ConfirmTOTP(ctx context.Context, userID int64, at time.Time, codeHashes []string) error
+	UseTOTPStep(ctx context.Context, userID, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error
+	DeleteTOTP(ctx context.Context, userID int64) error
+}
+
+type mfaTokenManager interface {
+	CreateMFAToken(userID int64) (string, error)
+	ParseMFAToken(tokenStr string) (int64, error)
+}
+
+// TwoFactorOptions configure two-factor authentication.
+type TwoFactorOptions struct {
+	// Enabled allows users to set up two-factor authentication.
+	// The mfa token manager is only used when it is set.
+	Enabled bool
+	// Issuer names the service in authenticator apps.
+	Issuer string
+}
+
+// SetupTwoFactor starts the enrollment of an authenticator app. Two-factor authentication
+// is enabled once a code of the app is confirmed with ConfirmTwoFactor.
+// Setting up again replaces a pending enrollment.
+func (u *UserUsecase) SetupTwoFactor(ctx context.Context, userID int64) (models.TOTPSetup, error) {
+	const op = "usecase.SetupTwoFactor"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	if !u.twoFactorOptions.Enabled {
+		log.Info("two-factor authentication is disabled")
+
+		return models.TOTPSetup{}, fmt.Errorf("%s: %w", op, ErrTwoFactorDisabled)
+	}
+
+	user, err := u.Profile(ctx, userID)
+	if err != nil {
+		return models.TOTPSetup{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Error("failed to generate totp secret", sl.Err(err))
+
+		return models.TOTPSetup{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.twoFactorRepository.SaveTOTP(ctx, userID, secret); err != nil {
+		if errors.Is(err, storage.ErrTOTPAlreadyEnabled) {
+			log.Info("two-factor authentication is already enabled")
+
+			return models.TOTPSetup{}, fmt.Errorf("%s: %w", op, ErrTwoFactorEnabled)
+		}
+
+		log.Error("failed to save totp", sl.Err(err))
+
+		return models.TOTPSetup{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.TOTPSetup{
+		Secret: secret,
+		URI:    totp.URI(u.twoFactorOptions.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication if the code matches the pending enrollment.
+// It returns the recovery codes, which are only stored hashed and can't be shown again.
+func (u *UserUsecase) ConfirmTwoFactor(ctx context.Context, userID int64, code string) ([]string, error) {
+	const op = "usecase.ConfirmTwoFactor"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	enrollment, err := u.twoFactorRepository.TOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrTOTPNotFound) {
+			log.Info("two-factor authentication is not set up")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrTwoFactorNotEnabled)
+		}
+
+		log.Error("failed to get totp", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if enrollment.ConfirmedAt != nil {
+		log.Info("two-factor authentication is already enabled")
+
+		return nil, fmt.Errorf("%s: %w", op, ErrTwoFactorEnabled)
+	}
+
+	if err := u.checkTOTP(ctx, &enrollment, code); err != nil {
+		log.Info("invalid totp code", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		log.Error("failed to generate recovery codes", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.twoFactorRepository.ConfirmTOTP(ctx, userID, time.Now(), hashes); err != nil {
+		log.Error("failed to confirm totp", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("two-factor authentication enabled")
+
+	return codes, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off. The code is either
+// a code of the authenticator app or a recovery code.
+func (u *UserUsecase) DisableTwoFactor(ctx context.Context, userID int64, code string) error {
+	const op = "usecase.DisableTwoFactor"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	enrollment, err := u.enabledTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			log.Info("two-factor authentication is not enabled")
+		} else {
+			log.Error("failed to get totp", sl.Err(err))
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.checkSecondFactor(ctx, &enrollment, code); err != nil {
+		log.Info("invalid second factor", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.twoFactorRepository.DeleteTOTP(ctx, userID); err != nil {
+		log.Error("failed to delete totp", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("two-factor authentication disabled")
+
+	return nil
+}
+
+// LoginTwoFactor finishes the login of a user with two-factor authentication.
+// The mfa token returned by Login is exchanged for a token pair if the code is
+// a code of the authenticator app or a recovery code.
+func (u *UserUsecase) LoginTwoFactor(ctx context.Context, mfaToken, code string, client models.Client) (models.Tokens, error) {
+	const op = "usecase.LoginTwoFactor"
+
+	log := u.logger.With(slog.String("op", op))
+
+	userID, err := u.MFATokenUserID(mfaToken)
+	if err != nil {
+		log.Info("invalid mfa token", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", userID))
+
+	// The token was issued for an enrollment that is gone.
+	enrollment, err := u.enabledTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			log.Info("two-factor authentication is not enabled")
+
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get totp", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.checkSecondFactor(ctx, &enrollment, code); err != nil {
+		log.Info("invalid second factor", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := u.userRepository.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get user from repository", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := u.getTokens(ctx, &user, client)
+	if err != nil {
+		log.Error("failed to create user tokens", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user successfully logged in with second factor")
+
+	return tokens, nil
+}
+
+// MFATokenUserID returns the id of the user the mfa token was issued to or ErrInvalidToken.
+func (u *UserUsecase) MFATokenUserID(mfaToken string) (int64, error) {
+	const op = "usecase.MFATokenUserID"
+
+	if !u.twoFactorOptions.Enabled {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	userID, err := u.mfaTokens.ParseMFAToken(mfaToken)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	return userID, nil
+}
+
+// loginTokens returns the tokens of the first login step: an mfa token if the user has
+// two-factor authentication enabled, a token pair otherwise. Users who enabled it can't
+// log in while the feature is disabled, rather than skip their second factor.
+func (u *UserUsecase) loginTokens(ctx context.Context, user *models.User, client models.Client) (models.Tokens, error) {
+	_, err := u.enabledTOTP(ctx, user.ID)
+	if errors.Is(err, ErrTwoFactorNotEnabled) {
+		return u.getTokens(ctx, user, client)
+	}
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	if !u.twoFactorOptions.Enabled {
+		return models.Tokens{}, ErrTwoFactorDisabled
+	}
+
+	mfaToken, err := u.mfaTokens.CreateMFAToken(user.ID)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	return models.Tokens{MFA: mfaToken}, nil
+}
+
+// enabledTOTP returns the confirmed totp of the user or ErrTwoFactorNotEnabled.
+func (u *UserUsecase) enabledTOTP(ctx context.Context, userID int64) (models.TOTP, error) {
+	enrollment, err := u.twoFactorRepository.TOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrTOTPNotFound) {
+			return models.TOTP{}, ErrTwoFactorNotEnabled
+		}
+
+		return models.TOTP{}, err
+	}
+
+	if enrollment.ConfirmedAt == nil {
+		return models.TOTP{}, ErrTwoFactorNotEnabled
+	}
+
+	return enrollment, nil
+}
+
+// checkSecondFactor accepts a code of the authenticator app or an unused recovery code.
+func (u *UserUsecase) checkSecondFactor(ctx context.Context, enrollment *models.TOTP, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return u.checkTOTP(ctx, enrollment, code)
+	}
+
+	err := u.twoFactorRepository.UseRecoveryCode(ctx, enrollment.UserID, hashRecoveryCode(code))
+	if errors.Is(err, storage.ErrRecoveryCodeNotFound) {
+		return ErrInvalidCode
+	}
+
+	return err
+}
+
+// checkTOTP accepts a code of the authenticator app. Every code can be used once.
+func (u *UserUsecase) checkTOTP(ctx context.Context, enrollment *models.TOTP, code string) error {
+	step, ok, err := totp.Match(enrollment.Secret, code, time.Now(), totpSkew)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCode
+	}
+
+	err = u.twoFactorRepository.UseTOTPStep(ctx, enrollment.UserID, step)
+	if errors.Is(err, storage.ErrTOTPStepUsed) {
+		return ErrInvalidCode
+	}
+
+	return err
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes returns recovery codes formatted like "abcde-fghij" and their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b)[:10])
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes the code ignoring case, spaces and dashes.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	return tokenutil.HashOpaqueToken(code)
+}
@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/totp"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+// totpCode returns the code of the authenticator app steps away from now.
+func totpCode(t *testing.T, secret string, steps int64) string {
+	t.Helper()
+
+	code, err := totp.Code(secret, totp.Step(time.Now())+steps)
+	require.NoError(t, err)
+
+	return code
+}
+
+func TestUserUsecase_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	u := newUserUsecase(memory.NewDB())
+
+	userID, email, password := createUser(t, u)
+
+	setup, err := u.SetupTwoFactor(ctx, userID)
+	require.NoError(t, err)
+	assert.Contains(t, setup.URI, "otpauth://totp/")
+	assert.Contains(t, setup.URI, "secret="+setup.Secret)
+
+	// Pending enrollments don't affect login.
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.Access)
+	assert.Empty(t, tokens.MFA)
+
+	_, err = u.ConfirmTwoFactor(ctx, userID, "000000")
+	assert.ErrorIs(t, err, usecase.ErrInvalidCode)
+
+	codes, err := u.ConfirmTwoFactor(ctx, userID, totpCode(t, setup.Secret, 0))
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+
+	_, err = u.SetupTwoFactor(ctx, userID)
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorEnabled)
+
+	tokens, err = u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+	assert.Empty(t, tokens.Access)
+	assert.Empty(t, tokens.Refresh)
+	require.NotEmpty(t, tokens.MFA)
+
+	// The code used for confirmation can't be replayed.
+	_, err = u.LoginTwoFactor(ctx, tokens.MFA, totpCode(t, setup.Secret, 0), testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCode)
+
+	_, err = u.LoginTwoFactor(ctx, "garbage", totpCode(t, setup.Secret, 1), testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	pair, err := u.LoginTwoFactor(ctx, tokens.MFA, totpCode(t, setup.Secret, 1), testClient)
+	require.NoError(t, err)
+
+	claims, err := u.Authenticate(ctx, pair.Access)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+
+	// Recovery codes work once, in any case and with or without the dash.
+	recovery := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+
+	_, err = u.LoginTwoFactor(ctx, tokens.MFA, recovery, testClient)
+	require.NoError(t, err)
+
+	_, err = u.LoginTwoFactor(ctx, tokens.MFA, codes[0], testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCode)
+
+	require.NoError(t, u.DisableTwoFactor(ctx, userID, codes[1]))
+
+	err = u.DisableTwoFactor(ctx, userID, codes[2])
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorNotEnabled)
+
+	// The mfa token is useless once two-factor authentication is disabled.
+	_, err = u.LoginTwoFactor(ctx, tokens.MFA, codes[2], testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	tokens, err = u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.Access)
+}
+
+func TestUserUsecase_ConfirmTwoFactor_NotSetUp(t *testing.T) {
+	u := newUserUsecase(memory.NewDB())
+
+	userID, _, _ := createUser(t, u)
+
+	_, err := u.ConfirmTwoFactor(context.Background(), userID, "123456")
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorNotEnabled)
+}
+
+func TestUserUsecase_TwoFactorDisabled(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	u := newUserUsecase(db)
+
+	userID, email, password := createUser(t, u)
+
+	setup, err := u.SetupTwoFactor(ctx, userID)
+	require.NoError(t, err)
+	_, err = u.ConfirmTwoFactor(ctx, userID, totpCode(t, setup.Secret, 0))
+	require.NoError(t, err)
+
+	disabled := usecase.NewUserUsecase(
+		password_hasher.NewBcryptPasswordHasher(),
+		memory.NewUserRepository(db),
+		memory.NewTokenRepository(db),
+		memory.NewTwoFactorRepository(db),
+		tokenutil.NewJWTTokenManager(accessSecret, refreshSecret, accessTTL, refreshTTL),
+		tokenutil.NewVerificationTokenManager(verificationSecret, time.Hour),
+		nil,
+		newMailSender(&mailbox{}),
+		usecase.VerificationOptions{},
+		usecase.TwoFactorOptions{},
+		newLogger(),
+	)
+
+	otherID, _, _ := createUser(t, disabled)
+	_, err = disabled.SetupTwoFactor(ctx, otherID)
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorDisabled)
+
+	// Enrolled users are refused rather than let in without the second factor.
+	_, err = disabled.Login(ctx, email, password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorDisabled)
+
+	_, err = disabled.LoginTwoFactor(ctx, "token", totpCode(t, setup.Secret, 1), testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
@@ -6,54 +6,105 @@ import (
 	"fmt"
 	"github.com/diproducts/application-tracker-go/internal/domain/models"
 	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
 	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"github.com/google/uuid"
 	"log/slog"
+	"time"
 )
 
 var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenReused        = errors.New("refresh token reused")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	ErrUserNotFound       = errors.New("user not found")
 )
 
 type userRepository interface {
 	SaveUser(ctx context.Context, user *models.User) (int64, error)
 	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	VerifyUser(ctx context.Context, id int64, email string, at time.Time) error
+	UpdatePassword(ctx context.Context, id int64, hashedPassword string) error
+	UpdateUser(ctx context.Context, user *models.User) error
+	DeleteUser(ctx context.Context, id int64) error
 }
 
 type passwordHasher interface {
 	Generate(password string) (string, error)
 	Compare(hashedPassword string, password string) error
+	NeedsRehash(hashedPassword string) bool
+}
+
+type tokenRepository interface {
+	BlacklistToken(ctx context.Context, userID int64, tokenID string, expiry time.Time) error
+	IsBlacklisted(ctx context.Context, tokenID string) (bool, error)
+	SaveFamily(ctx context.Context, session models.Session) error
+	UseFamily(ctx context.Context, familyID string, client models.Client, at time.Time) error
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUserFamilies(ctx context.Context, userID int64) error
+	DeleteExpiredTokens(ctx context.Context, before time.Time) (int64, error)
 }
 
 type tokenManager interface {
-	CreateUserAccessToken(user *models.User) (string, error)
-	CreateUserRefreshToken(user *models.User) (string, error)
+	CreateTokenPair(user *models.User, familyID string) (models.Tokens, error)
+	ParseAccessToken(tokenStr string) (models.TokenClaims, error)
+	ParseRefreshToken(tokenStr string) (models.TokenClaims, error)
+}
+
+type mailSender interface {
+	SendTemplate(ctx context.Context, to, locale, name string, data any) error
 }
 
 type UserUsecase struct {
-	passwordHasher passwordHasher
-	userRepository userRepository
-	tokenManager   tokenManager
-	logger         *slog.Logger
+	passwordHasher      passwordHasher
+	userRepository      userRepository
+	tokenRepository     tokenRepository
+	twoFactorRepository twoFactorRepository
+	tokenManager        tokenManager
+	verificationTokens  verificationTokenManager
+	mfaTokens           mfaTokenManager
+	mailSender          mailSender
+	verificationOptions VerificationOptions
+	twoFactorOptions    TwoFactorOptions
+	logger              *slog.Logger
 }
 
 func NewUserUsecase(
 	passwordHasher passwordHasher,
 	userRepository userRepository,
+	tokenRepository tokenRepository,
+	twoFactorRepository twoFactorRepository,
 	tokenManager tokenManager,
+	verificationTokens verificationTokenManager,
+	mfaTokens mfaTokenManager,
+	mailSender mailSender,
+	verificationOptions VerificationOptions,
+	twoFactorOptions TwoFactorOptions,
 	logger *slog.Logger,
 ) *UserUsecase {
 	return &UserUsecase{
-		passwordHasher: passwordHasher,
-		userRepository: userRepository,
-		tokenManager:   tokenManager,
-		logger:         logger,
+		passwordHasher:      passwordHasher,
+		userRepository:      userRepository,
+		tokenRepository:     tokenRepository,
+		twoFactorRepository: twoFactorRepository,
+		tokenManager:        tokenManager,
+		verificationTokens:  verificationTokens,
+		mfaTokens:           mfaTokens,
+		mailSender:          mailSender,
+		verificationOptions: verificationOptions,
+		twoFactorOptions:    twoFactorOptions,
+		logger:              logger,
 	}
 }
 
 // CreateUser creates a new user and stores in into the repository.
+// Emails to the user are written in locale, mailer.DefaultLocale if it is empty.
 // Returns an id of the created user and error.
-func (u *UserUsecase) CreateUser(ctx context.Context, email, password, name string) (int64, error) {
+func (u *UserUsecase) CreateUser(ctx context.Context, email, password, name, locale string) (int64, error) {
 	const op = "usecase.CreateUser"
 
 	log := u.logger.With(slog.String("op", op))
@@ -65,10 +116,15 @@ func (u *UserUsecase) CreateUser(ctx context.Context, email, password, name stri
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if locale == "" {
+		locale = mailer.DefaultLocale
+	}
+
 	user := models.User{
 		Email:          email,
 		HashedPassword: hashedPassword,
 		Name:           name,
+		Locale:         locale,
 	}
 
 	userId, err := u.userRepository.SaveUser(ctx, &user)
@@ -84,12 +140,20 @@ func (u *UserUsecase) CreateUser(ctx context.Context, email, password, name stri
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// The account is created even if the email can't be sent,
+	// the user can ask for another one.
+	user.ID = userId
+	if err := u.sendVerification(ctx, &user); err != nil {
+		log.Error("failed to send verification email", sl.Err(err))
+	}
+
 	return userId, nil
 }
 
 // Login checks if user exists and checks if the password is correct.
-// Returns access/refresh token or error.
-func (u *UserUsecase) Login(ctx context.Context, email, password string) (models.Tokens, error) {
+// Returns access/refresh token or error. Users with two-factor authentication
+// get an mfa token instead, see LoginTwoFactor. The client is recorded in the session.
+func (u *UserUsecase) Login(ctx context.Context, email, password string, client models.Client) (models.Tokens, error) {
 	const op = "usecase.Login"
 
 	log := u.logger.With(slog.String("op", op))
@@ -113,37 +177,270 @@ func (u *UserUsecase) Login(ctx context.Context, email, password string) (models
 		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 	}
 
-	tokens, err := u.getTokens(&user)
+	if u.passwordHasher.NeedsRehash(user.HashedPassword) {
+		u.rehashPassword(ctx, log, user.ID, password)
+	}
+
+	if u.verificationOptions.Required && user.VerifiedAt == nil {
+		log.Info("email is not verified")
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrEmailNotVerified)
+	}
+
+	tokens, err := u.loginTokens(ctx, &user, client)
 	if err != nil {
 		log.Error("failed to create user tokens", sl.Err(err))
 
 		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if tokens.MFA != "" {
+		log.Info("second factor required")
+
+		return tokens, nil
+	}
+
 	log.Info("user successfully logged in")
 
 	return tokens, nil
 }
 
-// Refresh validates the refresh token and provides a new access token.
-func (u *UserUsecase) Refresh(ctx context.Context, refreshToken string) (models.Tokens, error) {
-	// TODO: implement
-	return models.Tokens{}, nil
+// rehashPassword upgrades the stored hash of the user to the current algorithm and parameters.
+// The plain password is only known at login, so this is the only chance to do it. Failures
+// are logged and don't fail the login, the upgrade is attempted again on the next one.
+func (u *UserUsecase) rehashPassword(ctx context.Context, log *slog.Logger, userID int64, password string) {
+	hashedPassword, err := u.passwordHasher.Generate(password)
+	if err != nil {
+		log.Error("failed to rehash password", sl.Err(err))
+		return
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		log.Error("failed to save rehashed password", sl.Err(err))
+		return
+	}
+
+	log.Info("password hash upgraded", slog.Int64("user_id", userID))
 }
 
-func (u *UserUsecase) getTokens(user *models.User) (models.Tokens, error) {
-	accessToken, err := u.tokenManager.CreateUserAccessToken(user)
+// IssueTokens logs in a user authenticated by other means, such as an external identity provider.
+// Like Login, it refuses unverified emails when verification is required and returns
+// an mfa token instead of the pair for users with two-factor authentication.
+func (u *UserUsecase) IssueTokens(ctx context.Context, user *models.User, client models.Client) (models.Tokens, error) {
+	const op = "usecase.IssueTokens"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", user.ID))
+
+	if u.verificationOptions.Required && user.VerifiedAt == nil {
+		log.Info("email is not verified")
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrEmailNotVerified)
+	}
+
+	tokens, err := u.loginTokens(ctx, user, client)
 	if err != nil {
-		return models.Tokens{}, err
+		log.Error("failed to create user tokens", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// Refresh validates the refresh token and rotates it: the presented token is
+// blacklisted and a new access/refresh pair of the same family is returned.
+// Presenting an already rotated refresh token revokes the whole family.
+// The session of the family is updated with the client and the time of use.
+func (u *UserUsecase) Refresh(ctx context.Context, refreshToken string, client models.Client) (models.Tokens, error) {
+	const op = "usecase.Refresh"
+
+	log := u.logger.With(slog.String("op", op))
+
+	claims, err := u.tokenManager.ParseRefreshToken(refreshToken)
+	if err != nil {
+		log.Info("invalid refresh token", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	log = log.With(slog.Int64("user_id", claims.UserID), slog.String("family_id", claims.FamilyID))
+
+	if claims.FamilyID == "" {
+		log.Info("refresh token has no family")
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	revoked, err := u.tokenRepository.IsFamilyRevoked(ctx, claims.FamilyID)
+	if err != nil {
+		log.Error("failed to check token family", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if revoked {
+		log.Info("token family is revoked")
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	// Blacklisting is the atomic "use" of the refresh token:
+	// only one of concurrent requests with the same token succeeds.
+	err = u.tokenRepository.BlacklistToken(ctx, claims.UserID, claims.TokenID, claims.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenAlreadyBlacklisted) {
+			log.Warn("refresh token reuse detected, revoking token family")
+
+			if err := u.tokenRepository.RevokeFamily(ctx, claims.FamilyID); err != nil {
+				log.Error("failed to revoke token family", sl.Err(err))
+
+				return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+			}
+
+			return models.Tokens{}, fmt.Errorf("%s: %w", op, ErrTokenReused)
+		}
+
+		log.Error("failed to blacklist refresh token", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	refreshToken, err := u.tokenManager.CreateUserRefreshToken(user)
+	tokens, err := u.tokenManager.CreateTokenPair(&models.User{ID: claims.UserID}, claims.FamilyID)
 	if err != nil {
+		log.Error("failed to create user tokens", sl.Err(err))
+
+		return models.Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The presented token is already used up, failing now would log the user out.
+	if err := u.tokenRepository.UseFamily(ctx, claims.FamilyID, client, time.Now()); err != nil {
+		log.Error("failed to update session", sl.Err(err))
+	}
+
+	log.Info("tokens refreshed")
+
+	return tokens, nil
+}
+
+// Authenticate validates the access token and makes sure it wasn't revoked
+// by logout or by revocation of its token family.
+func (u *UserUsecase) Authenticate(ctx context.Context, accessToken string) (models.TokenClaims, error) {
+	const op = "usecase.Authenticate"
+
+	claims, err := u.tokenManager.ParseAccessToken(accessToken)
+	if err != nil {
+		return models.TokenClaims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	blacklisted, err := u.tokenRepository.IsBlacklisted(ctx, claims.TokenID)
+	if err != nil {
+		return models.TokenClaims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if blacklisted {
+		return models.TokenClaims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if claims.FamilyID != "" {
+		revoked, err := u.tokenRepository.IsFamilyRevoked(ctx, claims.FamilyID)
+		if err != nil {
+			return models.TokenClaims{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if revoked {
+			return models.TokenClaims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+	}
+
+	return claims, nil
+}
+
+// Logout revokes the access token and the token family it belongs to,
+// so the refresh token issued with it can't be used anymore.
+func (u *UserUsecase) Logout(ctx context.Context, claims models.TokenClaims) error {
+	const op = "usecase.Logout"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", claims.UserID))
+
+	err := u.tokenRepository.BlacklistToken(ctx, claims.UserID, claims.TokenID, claims.ExpiresAt)
+	if err != nil && !errors.Is(err, storage.ErrTokenAlreadyBlacklisted) {
+		log.Error("failed to blacklist access token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if claims.FamilyID != "" {
+		if err := u.tokenRepository.RevokeFamily(ctx, claims.FamilyID); err != nil {
+			log.Error("failed to revoke token family", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	log.Info("user logged out")
+
+	return nil
+}
+
+// LogoutAll revokes every token family of the user, logging them out on all devices.
+func (u *UserUsecase) LogoutAll(ctx context.Context, claims models.TokenClaims) error {
+	const op = "usecase.LogoutAll"
+
+	log := u.logger.With(slog.String("op", op), slog.Int64("user_id", claims.UserID))
+
+	err := u.tokenRepository.BlacklistToken(ctx, claims.UserID, claims.TokenID, claims.ExpiresAt)
+	if err != nil && !errors.Is(err, storage.ErrTokenAlreadyBlacklisted) {
+		log.Error("failed to blacklist access token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.tokenRepository.RevokeUserFamilies(ctx, claims.UserID); err != nil {
+		log.Error("failed to revoke token families", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged out from all devices")
+
+	return nil
+}
+
+// PurgeExpiredTokens removes blacklist entries of tokens that have already expired
+// and therefore can't pass validation anyway.
+func (u *UserUsecase) PurgeExpiredTokens(ctx context.Context) error {
+	const op = "usecase.PurgeExpiredTokens"
+
+	log := u.logger.With(slog.String("op", op))
+
+	n, err := u.tokenRepository.DeleteExpiredTokens(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to purge expired tokens", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Debug("expired tokens purged", slog.Int64("count", n))
+
+	return nil
+}
+
+// getTokens starts a new token family for the user, a session on the client,
+// and issues its first token pair.
+func (u *UserUsecase) getTokens(ctx context.Context, user *models.User, client models.Client) (models.Tokens, error) {
+	now := time.Now()
+	session := models.Session{
+		ID:         uuid.NewString(),
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		Created:    now,
+		LastUsedAt: now,
+	}
+
+	if err := u.tokenRepository.SaveFamily(ctx, session); err != nil {
 		return models.Tokens{}, err
 	}
 
-	return models.Tokens{
-		Access:  accessToken,
-		Refresh: refreshToken,
-	}, nil
+	return u.tokenManager.CreateTokenPair(user, session.ID)
 }
@@ -0,0 +1,372 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/password_hasher"
+	"github.com/diproducts/application-tracker-go/internal/lib/auth/tokenutil"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage/memory"
+	"github.com/diproducts/application-tracker-go/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	accessSecret       = "test_access_secret"
+	refreshSecret      = "test_refresh_secret"
+	verificationSecret = "test_verification_secret"
+	mfaSecret          = "test_mfa_secret"
+	accessTTL          = time.Duration(10 * time.Minute)
+	refreshTTL         = time.Duration(7 * 24 * time.Hour) // 1 week
+	testClient         = models.Client{UserAgent: "test-agent", IP: "192.0.2.1"}
+)
+
+func newLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// mailbox collects the messages sent by the usecases.
+// mailbox keeps the sent messages. It fails to send while err is set.
+type mailbox struct {
+	mu       sync.Mutex
+	messages []mailer.Message
+	err      error
+}
+
+func (m *mailbox) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.messages = append(m.messages, msg)
+
+	return nil
+}
+
+func (m *mailbox) last(t *testing.T) mailer.Message {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	require.NotEmpty(t, m.messages)
+
+	return m.messages[len(m.messages)-1]
+}
+
+// newMailSender renders the real email templates into box.
+func newMailSender(box *mailbox) *mailer.TemplateMailer {
+	templates, err := mailer.NewTemplates()
+	if err != nil {
+		panic(err)
+	}
+
+	return mailer.NewTemplateMailer(box, templates)
+}
+
+func newUserUsecase(db *memory.DB) *usecase.UserUsecase {
+	return newUserUsecaseWithMail(db, &mailbox{}, usecase.VerificationOptions{})
+}
+
+func newUserUsecaseWithMail(db *memory.DB, box *mailbox, opts usecase.VerificationOptions) *usecase.UserUsecase {
+	return newUserUsecaseWithHasher(db, password_hasher.NewBcryptPasswordHasher(), box, opts)
+}
+
+func newUserUsecaseWithHasher(
+	db *memory.DB,
+	hasher interface {
+		Generate(password string) (string, error)
+		Compare(hashedPassword string, password string) error
+		NeedsRehash(hashedPassword string) bool
+	},
+	box *mailbox,
+	opts usecase.VerificationOptions,
+) *usecase.UserUsecase {
+	return usecase.NewUserUsecase(
+		hasher,
+		memory.NewUserRepository(db),
+		memory.NewTokenRepository(db),
+		memory.NewTwoFactorRepository(db),
+		tokenutil.NewJWTTokenManager(accessSecret, refreshSecret, accessTTL, refreshTTL),
+		tokenutil.NewVerificationTokenManager(verificationSecret, time.Hour),
+		tokenutil.NewMFATokenManager(mfaSecret, time.Minute),
+		newMailSender(box),
+		opts,
+		usecase.TwoFactorOptions{Enabled: true, Issuer: "Application Tracker"},
+		newLogger(),
+	)
+}
+
+// createUser registers a random user and returns its id, email and password.
+func createUser(t *testing.T, u *usecase.UserUsecase) (int64, string, string) {
+	t.Helper()
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, false, false, 12)
+
+	id, err := u.CreateUser(context.Background(), email, password, gofakeit.Name(), "")
+	require.NoError(t, err)
+
+	return id, email, password
+}
+
+func TestUserUsecase_CreateUser(t *testing.T) {
+	ctx := context.Background()
+	u := newUserUsecase(memory.NewDB())
+
+	id, email, _ := createUser(t, u)
+	assert.NotZero(t, id)
+
+	_, err := u.CreateUser(ctx, email, gofakeit.Password(true, true, true, false, false, 12), gofakeit.Name(), "")
+	assert.ErrorIs(t, err, usecase.ErrUserAlreadyExists)
+}
+
+func TestUserUsecase_CreateUser_Locale(t *testing.T) {
+	ctx := context.Background()
+	box := &mailbox{}
+	u := newUserUsecaseWithMail(memory.NewDB(), box, usecase.VerificationOptions{})
+
+	_, err := u.CreateUser(ctx, gofakeit.Email(), "password", "", "ru-RU")
+	require.NoError(t, err)
+	assert.Equal(t, "Подтвердите адрес электронной почты", box.last(t).Subject)
+
+	// Locales without templates fall back to the default one.
+	_, err = u.CreateUser(ctx, gofakeit.Email(), "password", "", "xx")
+	require.NoError(t, err)
+	assert.Equal(t, "Confirm your email", box.last(t).Subject)
+}
+
+func TestUserUsecase_Login(t *testing.T) {
+	ctx := context.Background()
+	u := newUserUsecase(memory.NewDB())
+
+	userID, email, password := createUser(t, u)
+
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.Refresh)
+
+	claims, err := u.Authenticate(ctx, tokens.Access)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.NotEmpty(t, claims.FamilyID)
+
+	_, err = u.Login(ctx, email, "wrong"+password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	_, err = u.Login(ctx, "unknown"+email, password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+}
+
+func TestUserUsecase_Login_Rehash(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	users := memory.NewUserRepository(db)
+
+	_, email, password := createUser(t, newUserUsecase(db))
+
+	legacy, err := users.User(ctx, email)
+	require.NoError(t, err)
+
+	argon2id, err := password_hasher.NewArgon2idPasswordHasher(password_hasher.DefaultArgon2idParams)
+	require.NoError(t, err)
+	u := newUserUsecaseWithHasher(
+		db,
+		password_hasher.NewMultiPasswordHasher(argon2id, password_hasher.NewBcryptPasswordHasher()),
+		&mailbox{},
+		usecase.VerificationOptions{},
+	)
+
+	_, err = u.Login(ctx, email, "wrong"+password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	user, err := users.User(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, legacy.HashedPassword, user.HashedPassword)
+
+	_, err = u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	user, err = users.User(ctx, email)
+	require.NoError(t, err)
+	assert.True(t, argon2id.Matches(user.HashedPassword))
+
+	_, err = u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	again, err := users.User(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, user.HashedPassword, again.HashedPassword)
+}
+
+func TestUserUsecase_Refresh(t *testing.T) {
+	ctx := context.Background()
+	u := newUserUsecase(memory.NewDB())
+
+	_, email, password := createUser(t, u)
+
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	rotated, err := u.Refresh(ctx, tokens.Refresh, testClient)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.Refresh, rotated.Refresh)
+
+	_, err = u.Authenticate(ctx, rotated.Access)
+	require.NoError(t, err)
+
+	// Presenting the rotated token again revokes the whole family.
+	_, err = u.Refresh(ctx, tokens.Refresh, testClient)
+	assert.ErrorIs(t, err, usecase.ErrTokenReused)
+
+	_, err = u.Refresh(ctx, rotated.Refresh, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = u.Authenticate(ctx, rotated.Access)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = u.Refresh(ctx, "invalid", testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
+
+func TestUserUsecase_Logout(t *testing.T) {
+	ctx := context.Background()
+	u := newUserUsecase(memory.NewDB())
+
+	_, email, password := createUser(t, u)
+
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+	other, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	claims, err := u.Authenticate(ctx, tokens.Access)
+	require.NoError(t, err)
+
+	require.NoError(t, u.Logout(ctx, claims))
+
+	_, err = u.Authenticate(ctx, tokens.Access)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	_, err = u.Refresh(ctx, tokens.Refresh, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	// Other sessions are not affected.
+	_, err = u.Authenticate(ctx, other.Access)
+	assert.NoError(t, err)
+}
+
+func TestUserUsecase_LogoutAll(t *testing.T) {
+	ctx := context.Background()
+	u := newUserUsecase(memory.NewDB())
+
+	_, email, password := createUser(t, u)
+
+	tokens, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+	other, err := u.Login(ctx, email, password, testClient)
+	require.NoError(t, err)
+
+	claims, err := u.Authenticate(ctx, tokens.Access)
+	require.NoError(t, err)
+
+	require.NoError(t, u.LogoutAll(ctx, claims))
+
+	for _, pair := range []string{tokens.Access, other.Access} {
+		_, err = u.Authenticate(ctx, pair)
+		assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+	}
+
+	_, err = u.Refresh(ctx, other.Refresh, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
+
+func TestUserUsecase_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	box := &mailbox{}
+	u := newUserUsecaseWithMail(memory.NewDB(), box, usecase.VerificationOptions{
+		URL:      "https://tracker.example.com/verify",
+		Required: true,
+	})
+
+	_, email, password := createUser(t, u)
+
+	msg := box.last(t)
+	assert.Equal(t, email, msg.To)
+
+	_, err := u.Login(ctx, email, password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrEmailNotVerified)
+
+	// Wrong password is reported before the verification state.
+	_, err = u.Login(ctx, email, "wrong"+password, testClient)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	token := linkToken(t, msg.Text)
+
+	require.NoError(t, u.VerifyEmail(ctx, token))
+
+	_, err = u.Login(ctx, email, password, testClient)
+	assert.NoError(t, err)
+
+	// Tokens are single-use.
+	err = u.VerifyEmail(ctx, token)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+
+	err = u.VerifyEmail(ctx, "garbage")
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
+
+func TestUserUsecase_ResendVerification(t *testing.T) {
+	ctx := context.Background()
+	box := &mailbox{}
+	u := newUserUsecaseWithMail(memory.NewDB(), box, usecase.VerificationOptions{
+		URL: "https://tracker.example.com/verify",
+	})
+
+	_, email, _ := createUser(t, u)
+	require.Len(t, box.messages, 1)
+
+	require.NoError(t, u.ResendVerification(ctx, email))
+	require.Len(t, box.messages, 2)
+
+	// Failing to send doesn't tell the account exists either.
+	box.err = errors.New("smtp is down")
+	require.NoError(t, u.ResendVerification(ctx, email))
+	box.err = nil
+
+	require.NoError(t, u.VerifyEmail(ctx, linkToken(t, box.last(t).Text)))
+
+	// Neither verified nor unknown accounts get an email, and neither is reported.
+	require.NoError(t, u.ResendVerification(ctx, email))
+	require.NoError(t, u.ResendVerification(ctx, "unknown"+email))
+	assert.Len(t, box.messages, 2)
+}
+
+// linkToken extracts the token query parameter of the link in the email text.
+func linkToken(t *testing.T, text string) string {
+	t.Helper()
+
+	link := regexp.MustCompile(`https://\S+`).FindString(text)
+	require.NotEmpty(t, link)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+
+	token := u.Query().Get("token")
+	require.NotEmpty(t, token)
+
+	return token
+}
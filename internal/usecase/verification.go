@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/domain/models"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"github.com/diproducts/application-tracker-go/internal/mailer"
+	"github.com/diproducts/application-tracker-go/internal/repository/storage"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+type verificationTokenManager interface {
+	CreateVerificationToken(user *models.User) (string, error)
+	ParseVerificationToken(tokenStr string) (int64, string, error)
+}
+
+// VerificationOptions configure email verification of new accounts.
+type VerificationOptions struct {
+	// URL is the page verification links point to. The token is passed in the token query parameter.
+	// If it is empty, emails contain the bare token.
+	URL string
+	// Required blocks login until the email is verified.
+	Required bool
+}
+
+// VerifyEmail marks the email the token was issued for as verified.
+// Every token can be used once: verification fails if the email is already verified.
+func (u *UserUsecase) VerifyEmail(ctx context.Context, token string) error {
+	const op = "usecase.VerifyEmail"
+
+	log := u.logger.With(slog.String("op", op))
+
+	userID, email, err := u.verificationTokens.ParseVerificationToken(token)
+	if err != nil {
+		log.Info("invalid verification token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	log = log.With(slog.Int64("user_id", userID))
+
+	if err := u.userRepository.VerifyUser(ctx, userID, email, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("verification token is used or outdated")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to verify user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email verified")
+
+	return nil
+}
+
+// ResendVerification sends a new verification email if the account exists and isn't verified.
+// It doesn't report whether the account exists, so failures to send the email are only logged.
+func (u *UserUsecase) ResendVerification(ctx context.Context, email string) error {
+	const op = "usecase.ResendVerification"
+
+	log := u.logger.With(slog.String("op", op))
+
+	user, err := u.userRepository.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+
+			return nil
+		}
+
+		log.Error("failed to get user from repository", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.VerifiedAt != nil {
+		log.Info("email is already verified", slog.Int64("user_id", user.ID))
+
+		return nil
+	}
+
+	if err := u.sendVerification(ctx, &user); err != nil {
+		log.Error("failed to send verification email", slog.Int64("user_id", user.ID), sl.Err(err))
+	}
+
+	return nil
+}
+
+func (u *UserUsecase) sendVerification(ctx context.Context, user *models.User) error {
+	token, err := u.verificationTokens.CreateVerificationToken(user)
+	if err != nil {
+		return err
+	}
+
+	link, err := tokenLink(u.verificationOptions.URL, token)
+	if err != nil {
+		return err
+	}
+
+	return u.mailSender.SendTemplate(ctx, user.Email, user.Locale, mailer.TemplateVerification, mailer.TokenData{
+		Name:  user.Name,
+		Token: token,
+		Link:  link,
+	})
+}
+
+// tokenLink adds token to the query of the page URL. It returns an empty link if the URL is empty.
+func tokenLink(page, token string) (string, error) {
+	if page == "" {
+		return "", nil
+	}
+
+	link, err := url.Parse(page)
+	if err != nil {
+		return "", err
+	}
+
+	q := link.Query()
+	q.Set("token", token)
+	link.RawQuery = q.Encode()
+
+	return link.String(), nil
+}
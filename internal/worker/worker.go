@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"github.com/diproducts/application-tracker-go/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+)
+
+// Job is a unit of background work.
+type Job func(ctx context.Context) error
+
+// Periodic runs a job on a fixed interval until its context is cancelled.
+type Periodic struct {
+	name     string
+	interval time.Duration
+	job      Job
+	logger   *slog.Logger
+}
+
+// NewPeriodic returns an error if the interval isn't positive, a ticker can't run on it.
+func NewPeriodic(logger *slog.Logger, name string, interval time.Duration, job Job) (*Periodic, error) {
+	const op = "worker.NewPeriodic"
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("%s: %s: interval must be positive, got %s", op, name, interval)
+	}
+
+	return &Periodic{
+		name:     name,
+		interval: interval,
+		job:      job,
+		logger:   logger,
+	}, nil
+}
+
+// Run blocks until ctx is cancelled, running the job once per interval.
+// Job errors are logged and don't stop the worker.
+func (p *Periodic) Run(ctx context.Context) {
+	const op = "worker.Periodic.Run"
+
+	log := p.logger.With(slog.String("op", op), slog.String("worker", p.name))
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	log.Info("worker started", slog.Duration("interval", p.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("worker stopped")
+			return
+		case <-ticker.C:
+			if err := p.job(ctx); err != nil {
+				log.Error("job failed", sl.Err(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Package migrations embeds the sql migrations so they ship with the binaries.
+// Every supported database has its own directory with the same set of versions.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+const (
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+)
+
+// Dialects lists the directories of FS.
+var Dialects = []string{DialectPostgres, DialectSQLite}
+
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS
+
+// ForDialect returns the migrations of the given database dialect.
+func ForDialect(dialect string) (fs.FS, error) {
+	switch dialect {
+	case DialectPostgres, DialectSQLite:
+		return fs.Sub(FS, dialect)
+	default:
+		return nil, fmt.Errorf("unsupported migrations dialect %q", dialect)
+	}
+}
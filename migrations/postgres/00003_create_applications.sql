@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS applications
+(
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    company_name TEXT NOT NULL,
+    position TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    job_description TEXT NOT NULL DEFAULT '',
+    contacts TEXT NOT NULL DEFAULT '',
+    cv TEXT NOT NULL DEFAULT '',
+    cover_letter TEXT NOT NULL DEFAULT '',
+    offered_salary INTEGER NOT NULL DEFAULT 0,
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_modified TIMESTAMPTZ NOT NULL DEFAULT now(),
+    owner_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_applications_owner_id ON applications (owner_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS applications;
+-- +goose StatementEnd
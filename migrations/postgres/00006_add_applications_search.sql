@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', company_name), 'A') ||
+    setweight(to_tsvector('english', position), 'A') ||
+    setweight(to_tsvector('english', job_description), 'B') ||
+    setweight(to_tsvector('english', contacts), 'C') ||
+    setweight(to_tsvector('english', cover_letter), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_applications_search ON applications USING GIN (search);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_applications_search;
+ALTER TABLE applications DROP COLUMN IF EXISTS search;
+-- +goose StatementEnd
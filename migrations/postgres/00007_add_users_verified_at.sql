@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN verified_at TIMESTAMPTZ;
+-- Accounts created before email verification existed are treated as verified.
+UPDATE users SET verified_at = now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE token_families ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE token_families ADD COLUMN ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE token_families ADD COLUMN last_used_at TIMESTAMPTZ;
+-- Families issued before sessions existed were last used when they were created, as far as we know.
+UPDATE token_families SET last_used_at = created;
+CREATE INDEX IF NOT EXISTS idx_token_families_last_used_at ON token_families (last_used_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_token_families_last_used_at;
+ALTER TABLE token_families DROP COLUMN last_used_at;
+ALTER TABLE token_families DROP COLUMN ip;
+ALTER TABLE token_families DROP COLUMN user_agent;
+-- +goose StatementEnd
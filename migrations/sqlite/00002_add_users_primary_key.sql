@@ -0,0 +1,6 @@
+-- users.id is created as the primary key in 00001.
+-- The migration is kept to keep versions in line with postgres.
+
+-- +goose Up
+
+-- +goose Down
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS token_families
+(
+    id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_token_families_user_id ON token_families (user_id);
+
+CREATE TABLE IF NOT EXISTS token_blacklist
+(
+    token_id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expiry DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_token_blacklist_expiry ON token_blacklist (expiry);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS token_blacklist;
+DROP TABLE IF EXISTS token_families;
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- SQLite has no tsvector, applications are searched with LIKE instead.
+-- The migration is kept to keep versions in line with postgres.
+
+-- +goose Up
+
+-- +goose Down
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Whether the last request of the bucket was allowed, so that a single upsert can report it.
+ALTER TABLE rate_limit_buckets ADD COLUMN allowed BOOLEAN NOT NULL DEFAULT TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rate_limit_buckets DROP COLUMN allowed;
+-- +goose StatementEnd